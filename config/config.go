@@ -96,6 +96,8 @@ type Config struct {
 	configPath              string
 	configPathErr           error
 	godotEnvErr             error
+	observersLock           sync.RWMutex // protects the observers slice below
+	observers               []*observer
 }
 
 // GetBool gets bool value from config
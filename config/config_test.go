@@ -487,6 +487,37 @@ func TestReloadable(t *testing.T) {
 	})
 }
 
+func TestRegisterObserver(t *testing.T) {
+	c := New()
+	v := c.GetReloadableStringVar("default", "observed.key", "observed.legacyKey")
+	c.GetReloadableIntVar(1, 1, "other.key")
+
+	var notifications [][]string
+	var observedValue string
+	unregister := c.RegisterObserver(func(changedKeys []string) {
+		observedValue = v.Load()
+		// observers can register new variables without deadlocking
+		c.GetReloadableBoolVar(false, "registered.by.observer")
+		notifications = append(notifications, changedKeys)
+	})
+
+	c.Set("observed.key", "new")
+	require.Equal(t, [][]string{{"observed.key", "observed.legacyKey"}}, notifications)
+	require.Equal(t, "new", observedValue, "values are stored before observers are notified")
+
+	c.Set("unobserved.key", "value")
+	c.Set("observed.key", "new")
+	require.Len(t, notifications, 1, "observers aren't notified if no variable changed")
+
+	c.Set("other.key", 2)
+	require.Equal(t, []string{"other.key"}, notifications[1])
+
+	unregister()
+	c.Set("observed.key", "newer")
+	require.Len(t, notifications, 2, "unregistered observers aren't notified")
+	require.Equal(t, "newer", v.Load())
+}
+
 func TestConfigKeyToEnv(t *testing.T) {
 	expected := "RSERVER_KEY_VAR1_VAR2"
 	require.Equal(t, expected, ConfigKeyToEnv(DefaultEnvPrefix, "Key.Var1.Var2"))
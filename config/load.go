@@ -58,13 +58,54 @@ func (c *Config) onConfigChange() {
 		}
 	}()
 	c.hotReloadableConfigLock.RLock()
-	defer c.hotReloadableConfigLock.RUnlock()
-	c.checkAndHotReloadConfig(c.hotReloadableConfig)
+	changedKeys := c.checkAndHotReloadConfig(c.hotReloadableConfig)
+	c.hotReloadableConfigLock.RUnlock()
+	// observers are notified without holding the lock, so that they can read or register config variables
+	if len(changedKeys) > 0 {
+		c.notifyObservers(changedKeys)
+	}
+}
+
+// observer is a function registered through RegisterObserver
+type observer struct {
+	fn func(changedKeys []string)
+}
+
+// RegisterObserver registers a function to be called with the keys of the hot-reloadable variables of the default
+// config whose values changed, see Config.RegisterObserver
+func RegisterObserver(fn func(changedKeys []string)) (unregister func()) {
+	return Default.RegisterObserver(fn)
+}
+
+// RegisterObserver registers a function to be called with the keys of the hot-reloadable variables whose values changed,
+// once their new values are stored. It is called synchronously by the goroutine applying the change, e.g. the caller
+// of Set, thus it shouldn't block. The returned function unregisters the observer.
+func (c *Config) RegisterObserver(fn func(changedKeys []string)) (unregister func()) {
+	o := &observer{fn: fn}
+	c.observersLock.Lock()
+	c.observers = append(c.observers, o)
+	c.observersLock.Unlock()
+	return func() {
+		c.observersLock.Lock()
+		defer c.observersLock.Unlock()
+		c.observers = slices.DeleteFunc(c.observers, func(other *observer) bool { return other == o })
+	}
 }
 
-func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
+func (c *Config) notifyObservers(changedKeys []string) {
+	c.observersLock.RLock()
+	observers := slices.Clone(c.observers)
+	c.observersLock.RUnlock()
+	for _, o := range observers {
+		o.fn(changedKeys)
+	}
+}
+
+// checkAndHotReloadConfig reloads the values of the provided variables, returning the keys of the ones which changed
+func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) (changedKeys []string) {
 	for key, configValArr := range configMap {
 		for _, configVal := range configValArr {
+			var swapped bool
 			value := configVal.value
 			switch value := value.(type) {
 			case *int, *Reloadable[int]:
@@ -81,7 +122,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 					_value = configVal.defaultValue.(int)
 				}
 				_value = _value * configVal.multiplier.(int)
-				swapHotReloadableConfig(key, "%d", configVal, value, _value, compare[int]())
+				swapped = swapHotReloadableConfig(key, "%d", configVal, value, _value, compare[int]())
 			case *int64, *Reloadable[int64]:
 				var _value int64
 				var isSet bool
@@ -96,7 +137,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 					_value = configVal.defaultValue.(int64)
 				}
 				_value = _value * configVal.multiplier.(int64)
-				swapHotReloadableConfig(key, "%d", configVal, value, _value, compare[int64]())
+				swapped = swapHotReloadableConfig(key, "%d", configVal, value, _value, compare[int64]())
 			case *string, *Reloadable[string]:
 				var _value string
 				var isSet bool
@@ -110,7 +151,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 				if !isSet {
 					_value = configVal.defaultValue.(string)
 				}
-				swapHotReloadableConfig(key, "%q", configVal, value, _value, compare[string]())
+				swapped = swapHotReloadableConfig(key, "%q", configVal, value, _value, compare[string]())
 			case *time.Duration, *Reloadable[time.Duration]:
 				var _value time.Duration
 				var isSet bool
@@ -124,7 +165,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 				if !isSet {
 					_value = time.Duration(configVal.defaultValue.(int64)) * configVal.multiplier.(time.Duration)
 				}
-				swapHotReloadableConfig(key, "%d", configVal, value, _value, compare[time.Duration]())
+				swapped = swapHotReloadableConfig(key, "%d", configVal, value, _value, compare[time.Duration]())
 			case *bool, *Reloadable[bool]:
 				var _value bool
 				var isSet bool
@@ -138,7 +179,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 				if !isSet {
 					_value = configVal.defaultValue.(bool)
 				}
-				swapHotReloadableConfig(key, "%v", configVal, value, _value, compare[bool]())
+				swapped = swapHotReloadableConfig(key, "%v", configVal, value, _value, compare[bool]())
 			case *float64, *Reloadable[float64]:
 				var _value float64
 				var isSet bool
@@ -153,7 +194,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 					_value = configVal.defaultValue.(float64)
 				}
 				_value = _value * configVal.multiplier.(float64)
-				swapHotReloadableConfig(key, "%v", configVal, value, _value, compare[float64]())
+				swapped = swapHotReloadableConfig(key, "%v", configVal, value, _value, compare[float64]())
 			case *[]string, *Reloadable[[]string]:
 				var _value []string
 				var isSet bool
@@ -167,7 +208,7 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 				if !isSet {
 					_value = configVal.defaultValue.([]string)
 				}
-				swapHotReloadableConfig(key, "%v", configVal, value, _value, func(a, b []string) bool {
+				swapped = swapHotReloadableConfig(key, "%v", configVal, value, _value, func(a, b []string) bool {
 					return slices.Compare(a, b) == 0
 				})
 			case *map[string]interface{}, *Reloadable[map[string]interface{}]:
@@ -183,33 +224,44 @@ func (c *Config) checkAndHotReloadConfig(configMap map[string][]*configValue) {
 				if !isSet {
 					_value = configVal.defaultValue.(map[string]interface{})
 				}
-				swapHotReloadableConfig(key, "%v", configVal, value, _value, func(a, b map[string]interface{}) bool {
+				swapped = swapHotReloadableConfig(key, "%v", configVal, value, _value, func(a, b map[string]interface{}) bool {
 					return mapDeepEqual(a, b)
 				})
 			}
+			if swapped {
+				for _, k := range configVal.keys {
+					if !slices.Contains(changedKeys, k) {
+						changedKeys = append(changedKeys, k)
+					}
+				}
+			}
 		}
 	}
+	return changedKeys
 }
 
 func swapHotReloadableConfig[T configTypes](
 	key, placeholder string, configVal *configValue, ptr any, newValue T,
 	compare func(T, T) bool,
-) {
+) (swapped bool) {
 	if value, ok := ptr.(*T); ok {
 		if !compare(*value, newValue) {
 			fmt.Printf("The value of key %q & variable %p changed from "+placeholder+" to "+placeholder+"\n",
 				key, configVal, *value, newValue,
 			)
 			*value = newValue
+			return true
 		}
-		return
+		return false
 	}
 	reloadableValue, _ := configVal.value.(*Reloadable[T])
-	if oldValue, swapped := reloadableValue.swapIfNotEqual(newValue, compare); swapped {
+	oldValue, swapped := reloadableValue.swapIfNotEqual(newValue, compare)
+	if swapped {
 		fmt.Printf("The value of key %q & variable %p changed from "+placeholder+" to "+placeholder+"\n",
 			key, configVal, oldValue, newValue,
 		)
 	}
+	return swapped
 }
 
 type configValue struct {
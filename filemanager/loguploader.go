@@ -0,0 +1,27 @@
+package filemanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+// RotatedLogFileUploader returns a logger.RotatedFileHandler uploading the rotated log files to the file manager
+// with the given prefixes, e.g.
+//
+//	logger.NewFactory(c, logger.WithRotatedFileHandler(filemanager.RotatedLogFileUploader(fm, "logs", hostname)))
+func RotatedLogFileUploader(fm FileManager, prefixes ...string) logger.RotatedFileHandler {
+	return func(ctx context.Context, path string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening rotated log file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := fm.Upload(ctx, f, prefixes...); err != nil {
+			return fmt.Errorf("uploading rotated log file: %w", err)
+		}
+		return nil
+	}
+}
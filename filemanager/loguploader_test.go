@@ -0,0 +1,35 @@
+package filemanager_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/khulnasoft/go-kit/filemanager"
+	"github.com/khulnasoft/go-kit/filemanager/mock_filemanager"
+)
+
+func TestRotatedLogFileUploader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app-2077-01-23T10-15-13.000.log.gz")
+	require.NoError(t, os.WriteFile(path, []byte("logs"), 0o644))
+
+	ctrl := gomock.NewController(t)
+	fm := mock_filemanager.NewMockFileManager(ctrl)
+	fm.EXPECT().Upload(gomock.Any(), gomock.Any(), "logs", "host").DoAndReturn(
+		func(_ context.Context, f *os.File, _ ...string) (filemanager.UploadedFile, error) {
+			require.Equal(t, path, f.Name())
+			return filemanager.UploadedFile{}, nil
+		},
+	)
+	fm.EXPECT().Upload(gomock.Any(), gomock.Any(), "logs", "host").Return(filemanager.UploadedFile{}, errors.New("unavailable"))
+
+	upload := filemanager.RotatedLogFileUploader(fm, "logs", "host")
+	require.NoError(t, upload(context.Background(), path))
+	require.ErrorContains(t, upload(context.Background(), path), "unavailable")
+	require.Error(t, upload(context.Background(), filepath.Join(t.TempDir(), "missing.log.gz")))
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	kafkaProducerUniqName = "kafka_producer_%s_%s_%d"
+	kafkaConsumerUniqName = "kafka_consumer_%s_%s_%s"
+)
+
+// producerCollectorSeq is the sequence numbering the producer collectors
+var producerCollectorSeq atomic.Uint64
+
+// ProducerStatsReader is the interface implemented by Producer, for reading its statistics
+type ProducerStatsReader interface {
+	Stats() ProducerStats
+}
+
+// ConsumerStatsReader is the interface implemented by Consumer, for reading its statistics
+type ConsumerStatsReader interface {
+	Stats() ConsumerStats
+}
+
+// ProducerCollector collects the statistics of a Kafka producer, tagged by client ID (and topic if the producer
+// writes to a single topic). The ID of every collector is unique, since producers writing to several topics often
+// share the same (default) client ID.
+//
+// Since the producer resets its counters every time its stats are read, counters are accumulated by the collector and
+// reported as "_total" gauges. Durations are reported in microseconds and, like sizes, as the average and maximum
+// values observed since the previous collection.
+// The producer stats must not be read by anything else than this collector.
+type ProducerCollector struct {
+	producer ProducerStatsReader
+	seq      uint64 // distinguishes the collectors of producers with the same client ID and topic, see ID
+
+	mu     sync.Mutex
+	tags   stats.Tags
+	totals map[string]uint64
+}
+
+// NewProducerCollector creates a new collector for the given producer (i.e. a Producer)
+func NewProducerCollector(producer ProducerStatsReader) *ProducerCollector {
+	s := producer.Stats()
+	return &ProducerCollector{
+		producer: producer,
+		seq:      producerCollectorSeq.Add(1),
+		tags:     kafkaTags(s.ClientID, s.Topic, ""),
+		totals: map[string]uint64{
+			"kafka_producer_writes_total":   nonNegative(s.Writes),
+			"kafka_producer_messages_total": nonNegative(s.Messages),
+			"kafka_producer_bytes_total":    nonNegative(s.Bytes),
+			"kafka_producer_errors_total":   nonNegative(s.Errors),
+			"kafka_producer_retries_total":  nonNegative(s.Retries),
+			"kafka_producer_dials_total":    nonNegative(s.Dials),
+		},
+	}
+}
+
+func (s *ProducerCollector) Collect(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	ps := s.producer.Stats()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.totals["kafka_producer_writes_total"] += nonNegative(ps.Writes)
+	s.totals["kafka_producer_messages_total"] += nonNegative(ps.Messages)
+	s.totals["kafka_producer_bytes_total"] += nonNegative(ps.Bytes)
+	s.totals["kafka_producer_errors_total"] += nonNegative(ps.Errors)
+	s.totals["kafka_producer_retries_total"] += nonNegative(ps.Retries)
+	s.totals["kafka_producer_dials_total"] += nonNegative(ps.Dials)
+	for key, total := range s.totals {
+		gaugeFunc(key, s.tags, total)
+	}
+
+	kafkaDurationGauges(gaugeFunc, "kafka_producer_batch_time", s.tags, ps.BatchTime)
+	kafkaDurationGauges(gaugeFunc, "kafka_producer_batch_queue_time", s.tags, ps.BatchQueueTime)
+	kafkaDurationGauges(gaugeFunc, "kafka_producer_write_time", s.tags, ps.WriteTime)
+	kafkaDurationGauges(gaugeFunc, "kafka_producer_wait_time", s.tags, ps.WaitTime)
+	kafkaDurationGauges(gaugeFunc, "kafka_producer_dial_time", s.tags, ps.DialTime)
+	kafkaSummaryGauges(gaugeFunc, "kafka_producer_batch_size", s.tags, ps.BatchSize)
+	kafkaSummaryGauges(gaugeFunc, "kafka_producer_batch_bytes", s.tags, ps.BatchBytes)
+
+	gaugeFunc("kafka_producer_max_batch_size", s.tags, nonNegative(ps.MaxBatchSize))
+	gaugeFunc("kafka_producer_max_attempts", s.tags, nonNegative(ps.MaxAttempts))
+}
+
+func (s *ProducerCollector) Zero(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.totals {
+		gaugeFunc(key, s.tags, 0)
+	}
+	for _, key := range []string{
+		"kafka_producer_batch_time", "kafka_producer_batch_queue_time", "kafka_producer_write_time",
+		"kafka_producer_wait_time", "kafka_producer_dial_time",
+	} {
+		kafkaDurationGauges(gaugeFunc, key, s.tags, kafka.DurationStats{})
+	}
+	kafkaSummaryGauges(gaugeFunc, "kafka_producer_batch_size", s.tags, kafka.SummaryStats{})
+	kafkaSummaryGauges(gaugeFunc, "kafka_producer_batch_bytes", s.tags, kafka.SummaryStats{})
+	gaugeFunc("kafka_producer_max_batch_size", s.tags, 0)
+	gaugeFunc("kafka_producer_max_attempts", s.tags, 0)
+}
+
+func (s *ProducerCollector) ID() string {
+	return fmt.Sprintf(kafkaProducerUniqName, s.tags["clientId"], s.tags["topic"], s.seq)
+}
+
+// ConsumerCollector collects the statistics of a Kafka consumer, tagged by client ID, topic and partition.
+//
+// Since the consumer resets its counters every time its stats are read, counters are accumulated by the collector and
+// reported as "_total" gauges. Durations are reported in microseconds and, like sizes, as the average and maximum
+// values observed since the previous collection. Offset, lag and queue length are reported as they are.
+// The consumer stats must not be read by anything else than this collector.
+type ConsumerCollector struct {
+	consumer ConsumerStatsReader
+
+	mu     sync.Mutex
+	tags   stats.Tags
+	totals map[string]uint64
+}
+
+// NewConsumerCollector creates a new collector for the given consumer (i.e. a Consumer)
+func NewConsumerCollector(consumer ConsumerStatsReader) *ConsumerCollector {
+	s := consumer.Stats()
+	return &ConsumerCollector{
+		consumer: consumer,
+		tags:     kafkaTags(s.ClientID, s.Topic, s.Partition),
+		totals: map[string]uint64{
+			"kafka_consumer_dials_total":      nonNegative(s.Dials),
+			"kafka_consumer_fetches_total":    nonNegative(s.Fetches),
+			"kafka_consumer_messages_total":   nonNegative(s.Messages),
+			"kafka_consumer_bytes_total":      nonNegative(s.Bytes),
+			"kafka_consumer_rebalances_total": nonNegative(s.Rebalances),
+			"kafka_consumer_timeouts_total":   nonNegative(s.Timeouts),
+			"kafka_consumer_errors_total":     nonNegative(s.Errors),
+		},
+	}
+}
+
+func (s *ConsumerCollector) Collect(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	cs := s.consumer.Stats()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.totals["kafka_consumer_dials_total"] += nonNegative(cs.Dials)
+	s.totals["kafka_consumer_fetches_total"] += nonNegative(cs.Fetches)
+	s.totals["kafka_consumer_messages_total"] += nonNegative(cs.Messages)
+	s.totals["kafka_consumer_bytes_total"] += nonNegative(cs.Bytes)
+	s.totals["kafka_consumer_rebalances_total"] += nonNegative(cs.Rebalances)
+	s.totals["kafka_consumer_timeouts_total"] += nonNegative(cs.Timeouts)
+	s.totals["kafka_consumer_errors_total"] += nonNegative(cs.Errors)
+	for key, total := range s.totals {
+		gaugeFunc(key, s.tags, total)
+	}
+
+	kafkaDurationGauges(gaugeFunc, "kafka_consumer_dial_time", s.tags, cs.DialTime)
+	kafkaDurationGauges(gaugeFunc, "kafka_consumer_read_time", s.tags, cs.ReadTime)
+	kafkaDurationGauges(gaugeFunc, "kafka_consumer_wait_time", s.tags, cs.WaitTime)
+	kafkaSummaryGauges(gaugeFunc, "kafka_consumer_fetch_size", s.tags, cs.FetchSize)
+	kafkaSummaryGauges(gaugeFunc, "kafka_consumer_fetch_bytes", s.tags, cs.FetchBytes)
+
+	gaugeFunc("kafka_consumer_offset", s.tags, nonNegative(cs.Offset))
+	gaugeFunc("kafka_consumer_lag", s.tags, nonNegative(cs.Lag))
+	gaugeFunc("kafka_consumer_queue_length", s.tags, nonNegative(cs.QueueLength))
+	gaugeFunc("kafka_consumer_queue_capacity", s.tags, nonNegative(cs.QueueCapacity))
+}
+
+func (s *ConsumerCollector) Zero(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.totals {
+		gaugeFunc(key, s.tags, 0)
+	}
+	for _, key := range []string{"kafka_consumer_dial_time", "kafka_consumer_read_time", "kafka_consumer_wait_time"} {
+		kafkaDurationGauges(gaugeFunc, key, s.tags, kafka.DurationStats{})
+	}
+	kafkaSummaryGauges(gaugeFunc, "kafka_consumer_fetch_size", s.tags, kafka.SummaryStats{})
+	kafkaSummaryGauges(gaugeFunc, "kafka_consumer_fetch_bytes", s.tags, kafka.SummaryStats{})
+	for _, key := range []string{
+		"kafka_consumer_offset", "kafka_consumer_lag", "kafka_consumer_queue_length", "kafka_consumer_queue_capacity",
+	} {
+		gaugeFunc(key, s.tags, 0)
+	}
+}
+
+func (s *ConsumerCollector) ID() string {
+	return fmt.Sprintf(kafkaConsumerUniqName, s.tags["clientId"], s.tags["topic"], s.tags["partition"])
+}
+
+func kafkaTags(clientID, topic, partition string) stats.Tags {
+	tags := stats.Tags{"clientId": clientID}
+	if topic != "" {
+		tags["topic"] = topic
+	}
+	if partition != "" {
+		tags["partition"] = partition
+	}
+	return tags
+}
+
+func kafkaDurationGauges(
+	gaugeFunc func(key string, tag stats.Tags, val uint64), key string, tags stats.Tags, d kafka.DurationStats,
+) {
+	gaugeFunc(key+"_avg_microseconds", tags, nonNegative(int64(d.Avg/time.Microsecond)))
+	gaugeFunc(key+"_max_microseconds", tags, nonNegative(int64(d.Max/time.Microsecond)))
+}
+
+func kafkaSummaryGauges(
+	gaugeFunc func(key string, tag stats.Tags, val uint64), key string, tags stats.Tags, s kafka.SummaryStats,
+) {
+	gaugeFunc(key+"_avg", tags, nonNegative(s.Avg))
+	gaugeFunc(key+"_max", tags, nonNegative(s.Max))
+}
+
+// nonNegative converts n to an uint64, negative values (e.g. an unknown lag) being reported as 0
+func nonNegative(n int64) uint64 {
+	if n < 0 {
+		return 0
+	}
+	return uint64(n)
+}
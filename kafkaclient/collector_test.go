@@ -0,0 +1,135 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+type mockKafkaProducer struct{ stats []ProducerStats }
+
+func (m *mockKafkaProducer) Stats() (s ProducerStats) {
+	s, m.stats = m.stats[0], m.stats[1:]
+	return s
+}
+
+type mockKafkaConsumer struct{ stats []ConsumerStats }
+
+func (m *mockKafkaConsumer) Stats() (s ConsumerStats) {
+	s, m.stats = m.stats[0], m.stats[1:]
+	return s
+}
+
+func TestProducerCollector(t *testing.T) {
+	producer := &mockKafkaProducer{stats: []ProducerStats{
+		{ClientID: "client", Messages: 1},
+		{
+			ClientID: "client", Writes: 2, Messages: 10, Bytes: 100, Errors: 1, Retries: 3, Dials: 1,
+			WriteTime:    kafka.DurationStats{Avg: 2 * time.Millisecond, Max: 5 * time.Millisecond},
+			WaitTime:     kafka.DurationStats{Avg: time.Millisecond, Max: 3 * time.Millisecond},
+			DialTime:     kafka.DurationStats{Avg: 10 * time.Millisecond, Max: 10 * time.Millisecond},
+			BatchSize:    kafka.SummaryStats{Avg: 5, Max: 8},
+			MaxBatchSize: 100, MaxAttempts: 3,
+		},
+		{ClientID: "client", Writes: 1, Messages: 5, Bytes: 50, MaxBatchSize: 100, MaxAttempts: 3},
+	}}
+
+	m, err := memstats.New()
+	require.NoError(t, err)
+
+	c := NewProducerCollector(producer)
+	require.Regexp(t, `^kafka_producer_client__\d+$`, c.ID())
+	other := NewProducerCollector(&mockKafkaProducer{stats: []ProducerStats{{ClientID: "client"}}})
+	require.NotEqual(t, c.ID(), other.ID(), "producers with the same client ID have different collectors")
+	require.NoError(t, m.RegisterCollector(c))
+
+	tags := stats.Tags{"clientId": "client"}
+	require.EqualValues(t, 2, m.Get("kafka_producer_writes_total", tags).LastValue())
+	require.EqualValues(t, 11, m.Get("kafka_producer_messages_total", tags).LastValue())
+	require.EqualValues(t, 100, m.Get("kafka_producer_bytes_total", tags).LastValue())
+	require.EqualValues(t, 1, m.Get("kafka_producer_errors_total", tags).LastValue())
+	require.EqualValues(t, 3, m.Get("kafka_producer_retries_total", tags).LastValue())
+	require.EqualValues(t, 1, m.Get("kafka_producer_dials_total", tags).LastValue())
+	require.EqualValues(t, 2000, m.Get("kafka_producer_write_time_avg_microseconds", tags).LastValue())
+	require.EqualValues(t, 5000, m.Get("kafka_producer_write_time_max_microseconds", tags).LastValue())
+	require.EqualValues(t, 1000, m.Get("kafka_producer_wait_time_avg_microseconds", tags).LastValue())
+	require.EqualValues(t, 10000, m.Get("kafka_producer_dial_time_max_microseconds", tags).LastValue())
+	require.EqualValues(t, 5, m.Get("kafka_producer_batch_size_avg", tags).LastValue())
+	require.EqualValues(t, 8, m.Get("kafka_producer_batch_size_max", tags).LastValue())
+	require.EqualValues(t, 100, m.Get("kafka_producer_max_batch_size", tags).LastValue())
+	require.EqualValues(t, 3, m.Get("kafka_producer_max_attempts", tags).LastValue())
+
+	c.Collect(func(key string, tags stats.Tags, val uint64) {
+		m.NewTaggedStat(key, stats.GaugeType, tags).Gauge(val)
+	})
+	require.EqualValues(t, 3, m.Get("kafka_producer_writes_total", tags).LastValue(), "counters should be accumulated")
+	require.EqualValues(t, 16, m.Get("kafka_producer_messages_total", tags).LastValue())
+	require.EqualValues(t, 0, m.Get("kafka_producer_write_time_avg_microseconds", tags).LastValue())
+
+	c.Zero(func(key string, tags stats.Tags, val uint64) {
+		require.Zero(t, val, key)
+	})
+}
+
+func TestConsumerCollector(t *testing.T) {
+	consumer := &mockKafkaConsumer{stats: []ConsumerStats{
+		{ClientID: "client", Topic: "topic", Partition: "1"},
+		{
+			ClientID: "client", Topic: "topic", Partition: "1",
+			Dials: 1, Fetches: 4, Messages: 20, Bytes: 200, Rebalances: 1, Timeouts: 2, Errors: 1,
+			ReadTime:   kafka.DurationStats{Avg: time.Millisecond, Max: 2 * time.Millisecond},
+			FetchBytes: kafka.SummaryStats{Avg: 50, Max: 80},
+			Offset:     42, Lag: 7, QueueLength: 3, QueueCapacity: 100,
+		},
+		{ClientID: "client", Topic: "topic", Partition: "1", Messages: 1, Lag: -1},
+	}}
+
+	m, err := memstats.New()
+	require.NoError(t, err)
+
+	c := NewConsumerCollector(consumer)
+	require.Equal(t, "kafka_consumer_client_topic_1", c.ID())
+	require.NoError(t, m.RegisterCollector(c))
+
+	tags := stats.Tags{"clientId": "client", "topic": "topic", "partition": "1"}
+	require.EqualValues(t, 1, m.Get("kafka_consumer_dials_total", tags).LastValue())
+	require.EqualValues(t, 4, m.Get("kafka_consumer_fetches_total", tags).LastValue())
+	require.EqualValues(t, 20, m.Get("kafka_consumer_messages_total", tags).LastValue())
+	require.EqualValues(t, 200, m.Get("kafka_consumer_bytes_total", tags).LastValue())
+	require.EqualValues(t, 1, m.Get("kafka_consumer_rebalances_total", tags).LastValue())
+	require.EqualValues(t, 2, m.Get("kafka_consumer_timeouts_total", tags).LastValue())
+	require.EqualValues(t, 1, m.Get("kafka_consumer_errors_total", tags).LastValue())
+	require.EqualValues(t, 1000, m.Get("kafka_consumer_read_time_avg_microseconds", tags).LastValue())
+	require.EqualValues(t, 2000, m.Get("kafka_consumer_read_time_max_microseconds", tags).LastValue())
+	require.EqualValues(t, 50, m.Get("kafka_consumer_fetch_bytes_avg", tags).LastValue())
+	require.EqualValues(t, 42, m.Get("kafka_consumer_offset", tags).LastValue())
+	require.EqualValues(t, 7, m.Get("kafka_consumer_lag", tags).LastValue())
+	require.EqualValues(t, 3, m.Get("kafka_consumer_queue_length", tags).LastValue())
+	require.EqualValues(t, 100, m.Get("kafka_consumer_queue_capacity", tags).LastValue())
+
+	c.Collect(func(key string, tags stats.Tags, val uint64) {
+		m.NewTaggedStat(key, stats.GaugeType, tags).Gauge(val)
+	})
+	require.EqualValues(t, 21, m.Get("kafka_consumer_messages_total", tags).LastValue())
+	require.EqualValues(t, 0, m.Get("kafka_consumer_lag", tags).LastValue(), "unknown lag should be reported as zero")
+}
+
+func TestProducerCollectorWithProducer(t *testing.T) {
+	client, err := New("tcp", []string{"localhost:9092"}, Config{ClientID: "client"})
+	require.NoError(t, err)
+	producer, err := client.NewProducer(ProducerConfig{ClientID: "producer", BatchSize: 10})
+	require.NoError(t, err)
+
+	m, err := memstats.New()
+	require.NoError(t, err)
+	require.NoError(t, m.RegisterCollector(NewProducerCollector(producer)))
+
+	tags := stats.Tags{"clientId": "producer"}
+	require.EqualValues(t, 0, m.Get("kafka_producer_messages_total", tags).LastValue())
+	require.EqualValues(t, 10, m.Get("kafka_producer_max_batch_size", tags).LastValue())
+}
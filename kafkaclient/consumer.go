@@ -26,6 +26,9 @@ type ConsumerConfig struct {
 	ErrorLogger         Logger
 }
 
+// ConsumerStats are the statistics of a consumer, see kafka.ReaderStats
+type ConsumerStats = kafka.ReaderStats
+
 // Consumer provides a high-level API for reading messages from Kafka
 type Consumer struct {
 	reader *kafka.Reader
@@ -104,6 +107,12 @@ func (c *Consumer) Receive(ctx context.Context) (Message, error) {
 	}, nil
 }
 
+// Stats returns the statistics of the consumer since the last call to Stats.
+// Counters and summaries are reset on every call, so only one caller should periodically collect them.
+func (c *Consumer) Stats() ConsumerStats {
+	return c.reader.Stats()
+}
+
 func (c *Consumer) Ack(ctx context.Context, msgs ...Message) error {
 	internalMsgs := make([]kafka.Message, 0, len(msgs))
 	for _, msg := range msgs {
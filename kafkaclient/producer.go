@@ -51,10 +51,14 @@ func (c *ProducerConfig) defaults() {
 	}
 }
 
+// ProducerStats are the statistics of a producer, see kafka.WriterStats
+type ProducerStats = kafka.WriterStats
+
 // Producer provides a high-level API for producing messages to Kafka
 type Producer struct {
-	writer *kafka.Writer
-	config ProducerConfig
+	writer   *kafka.Writer
+	config   ProducerConfig
+	clientID string
 }
 
 // NewProducer instantiates a new producer. To use it asynchronously just do "go p.Publish(ctx, msgs)".
@@ -84,7 +88,8 @@ func (c *Client) NewProducer(producerConf ProducerConfig) (p *Producer, err erro
 	}
 
 	p = &Producer{
-		config: producerConf,
+		config:   producerConf,
+		clientID: transport.ClientID,
 		writer: &kafka.Writer{
 			Addr:                   kafka.TCP(c.addresses...),
 			Balancer:               &kafka.ReferenceHash{},
@@ -123,6 +128,14 @@ func (p *Producer) Close(ctx context.Context) error {
 	}
 }
 
+// Stats returns the statistics of the producer since the last call to Stats.
+// Counters and summaries are reset on every call, so only one caller should periodically collect them.
+func (p *Producer) Stats() ProducerStats {
+	s := p.writer.Stats()
+	s.ClientID = p.clientID
+	return s
+}
+
 // Publish allows the production of one or more message to Kafka.
 // To use it asynchronously just do "go p.Publish(ctx, msgs)".
 func (p *Producer) Publish(ctx context.Context, msgs ...Message) error {
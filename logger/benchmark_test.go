@@ -96,3 +96,31 @@ func BenchmarkZap(b *testing.B) {
 		})
 	})
 }
+
+// BenchmarkEntryMetrics measures the overhead of counting entries, which should be negligible on disabled levels
+func BenchmarkEntryMetrics(b *testing.B) {
+	c := config.New()
+	c.Set("LOG_LEVEL", "INFO")
+	c.Set("Logger.discardConsole", true)
+	f := NewFactory(c, WithCounters(func(string, map[string]string) Counter { return nopCounter{} }))
+	l := f.NewLogger().Child("module")
+
+	b.Run("enabled level", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				l.Infon("test", NewStringField("key1", "111"))
+			}
+		})
+	})
+	b.Run("disabled level", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				l.Debugn("test", NewStringField("key1", "111"))
+			}
+		})
+	})
+}
+
+type nopCounter struct{}
+
+func (nopCounter) Count(int) {}
@@ -2,7 +2,11 @@ package logger
 
 import (
 	"errors"
+	"io"
+	"maps"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap/zapcore"
 
@@ -11,15 +15,72 @@ import (
 
 // factoryConfig is the configuration for the logger
 type factoryConfig struct {
-	rootLevel        int                      // the level for the root logger
-	enableNameInLog  bool                     // whether to include the logger name in the log message
+	rootLevel        atomic.Int64             // the level for the root logger
 	enableStackTrace *config.Reloadable[bool] // for fatal logs
 
-	levelConfig      *syncMap[string, int] // preconfigured log levels for loggers
-	levelConfigCache *syncMap[string, int] // cache of all calculated log levels for loggers
+	levelConfig      *syncMap[string, int]                // preconfigured log levels for loggers
+	levelConfigCache atomic.Pointer[syncMap[string, int]] // cache of all calculated log levels for loggers
+
+	// hot-reloadable LOG_LEVEL and Logger.moduleLevels, reloaded by a config observer, see reload
+	rootLevelConfig    config.ValueLoader[string]
+	moduleLevelsConfig config.ValueLoader[string]
+	loadedLevels       atomic.Pointer[loadedLevels]
+	reloadMu           sync.Mutex
+	unregisterObserver func() // see Factory.Close
 
 	// zap specific config
-	clock zapcore.Clock
+	clock   zapcore.Clock
+	cores   []zapcore.Core // additional cores, e.g. the one capturing the entries of a TestFactory
+	closers []io.Closer    // closed along with the factory, e.g. the writer of the log file and the otlp exporter
+
+	newCounter         func(name string, tags map[string]string) Counter // for reporting the logger's metrics, see WithCounters
+	rotatedFileHandler RotatedFileHandler                                // see WithRotatedFileHandler
+	redactor           *redactor                                         // for redacting what isn't redacted by the zap core, e.g. request headers
+	serviceName        string                                            // for the resource of the log records exported over OTLP
+	serviceVersion     string                                            // for the resource of the log records exported over OTLP
+}
+
+// loadedLevels are the levels last loaded from the configuration
+type loadedLevels struct {
+	rootLevel    string
+	moduleLevels string
+	modules      map[string]int
+}
+
+// reload applies the changes of LOG_LEVEL and Logger.moduleLevels since they were last loaded, if any.
+// Only the levels which changed in the configuration are applied, thus levels set at runtime (e.g. through
+// SetLogLevel) for other modules are kept. Invalid values are ignored.
+func (fc *factoryConfig) reload() {
+	rootLevel, moduleLevels := fc.rootLevelConfig.Load(), fc.moduleLevelsConfig.Load()
+	if l := fc.loadedLevels.Load(); l != nil && l.rootLevel == rootLevel && l.moduleLevels == moduleLevels {
+		return
+	}
+
+	fc.reloadMu.Lock()
+	defer fc.reloadMu.Unlock()
+	loaded := fc.loadedLevels.Load()
+	if loaded == nil {
+		loaded = &loadedLevels{modules: map[string]int{}}
+		fc.rootLevel.Store(int64(levelMap[rootLevel]))
+	} else if rootLevel != loaded.rootLevel {
+		if level, ok := levelMap[rootLevel]; ok {
+			fc.rootLevel.Store(int64(level))
+		}
+	}
+
+	modules := parseModuleLevels(moduleLevels)
+	for module := range loaded.modules {
+		if _, ok := modules[module]; !ok {
+			fc.levelConfig.delete(module)
+		}
+	}
+	for module, level := range modules {
+		if oldLevel, ok := loaded.modules[module]; !ok || oldLevel != level {
+			fc.levelConfig.set(module, level)
+		}
+	}
+	fc.loadedLevels.Store(&loadedLevels{rootLevel: rootLevel, moduleLevels: moduleLevels, modules: modules})
+	fc.invalidateCache()
 }
 
 // SetLogLevel sets the log level for the given logger name
@@ -28,22 +89,40 @@ func (fc *factoryConfig) SetLogLevel(name, levelStr string) error {
 	if !ok {
 		return errors.New("invalid level value : " + levelStr)
 	}
+	fc.setLogLevel(name, level)
+	return nil
+}
+
+// setLogLevel sets the log level for the given logger name, the root logger if empty
+func (fc *factoryConfig) setLogLevel(name string, level int) {
 	if name == "" {
-		fc.rootLevel = level
+		fc.rootLevel.Store(int64(level))
 	} else {
 		fc.levelConfig.set(name, level)
 	}
-	fc.levelConfigCache = newSyncMap[string, int]()
-	return nil
+	fc.invalidateCache()
+}
+
+// unsetLogLevel removes the log level configured for the given logger name, which then inherits its parent's level
+func (fc *factoryConfig) unsetLogLevel(name string) {
+	fc.levelConfig.delete(name)
+	fc.invalidateCache()
+}
+
+// invalidateCache discards the calculated log levels. Since getOrSetLogLevel loads the cache before reading the
+// configuration, levels calculated concurrently with a change end up in the discarded cache.
+func (fc *factoryConfig) invalidateCache() {
+	fc.levelConfigCache.Store(newSyncMap[string, int]())
 }
 
 // getOrSetLogLevel returns the log level for the given logger name or sets it using the provided function if no level is set
 func (fc *factoryConfig) getOrSetLogLevel(name string, parentLevelFunc func() int) int {
 	if name == "" {
-		return fc.rootLevel
+		return int(fc.rootLevel.Load())
 	}
 
-	if level, found := fc.levelConfigCache.get(name); found {
+	cache := fc.levelConfigCache.Load()
+	if level, found := cache.get(name); found {
 		return level
 	}
 	level := func() int { // either get the level from the config or use the parent's level
@@ -52,10 +131,22 @@ func (fc *factoryConfig) getOrSetLogLevel(name string, parentLevelFunc func() in
 		}
 		return parentLevelFunc()
 	}()
-	fc.levelConfigCache.set(name, level) // cache the level
+	cache.set(name, level) // cache the level
 	return level
 }
 
+// moduleLevel returns the log level of the given module, inherited from its closest parent if not configured, like
+// the level of the logger of the module (see logger.getLoggingLevel)
+func (fc *factoryConfig) moduleLevel(module string) int {
+	return fc.getOrSetLogLevel(module, func() int {
+		i := strings.LastIndexByte(module, '.')
+		if i < 0 {
+			return fc.getOrSetLogLevel("", nil)
+		}
+		return fc.moduleLevel(module[:i])
+	})
+}
+
 // newSyncMap creates a new syncMap
 func newSyncMap[K comparable, V any]() *syncMap[K, V] {
 	return &syncMap[K, V]{m: map[K]V{}}
@@ -79,3 +170,16 @@ func (sm *syncMap[K, V]) set(key K, value V) {
 	defer sm.mu.Unlock()
 	sm.m[key] = value
 }
+
+func (sm *syncMap[K, V]) delete(key K) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.m, key)
+}
+
+// copy returns a copy of the map
+func (sm *syncMap[K, V]) copy() map[K]V {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return maps.Clone(sm.m)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the key of the field holding the id of the trace active in the context, see Logger.WithContext
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the key of the field holding the id of the span active in the context, see Logger.WithContext
+	SpanIDKey = "span_id"
+)
+
+type fieldsContextKey struct{}
+
+// ContextWithFields returns a copy of the context holding the given request-scoped fields (e.g. a request id or a
+// workspace id) along with the ones already held by the context. Fields are added to the loggers derived through
+// Logger.WithContext.
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent := FieldsFromContext(ctx)
+	res := make([]Field, 0, len(parent)+len(fields))
+	res = append(res, parent...)
+	res = append(res, fields...)
+	return context.WithValue(ctx, fieldsContextKey{}, res)
+}
+
+// FieldsFromContext returns the request-scoped fields held by the context, see ContextWithFields
+func FieldsFromContext(ctx context.Context) []Field {
+	fields, _ := ctx.Value(fieldsContextKey{}).([]Field)
+	return fields
+}
+
+// contextFields returns the fields a logger derived from the context should have: the ids of the active trace and span,
+// if any, followed by the request-scoped fields
+func contextFields(ctx context.Context) []Field {
+	fields := FieldsFromContext(ctx)
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return fields
+	}
+	res := make([]Field, 0, len(fields)+2)
+	res = append(res, NewStringField(TraceIDKey, sc.TraceID().String()), NewStringField(SpanIDKey, sc.SpanID().String()))
+	return append(res, fields...)
+}
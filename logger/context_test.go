@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestWithContext(t *testing.T) {
+	fileName := t.TempDir() + "out.log"
+	c := config.New()
+	c.Set("Logger.enableTimestamp", false)
+	c.Set("Logger.enableConsole", false)
+	c.Set("Logger.enableFile", true)
+	c.Set("Logger.enableFileNameInLog", false)
+	c.Set("Logger.logFileLocation", fileName)
+	loggerFactory := logger.NewFactory(c)
+	t.Cleanup(func() { _ = loggerFactory.Close() })
+	log := loggerFactory.NewLogger().Child("module")
+
+	ctx := context.Background()
+	require.Same(t, log, log.WithContext(ctx), "no fields to add")
+
+	ctx = logger.ContextWithFields(ctx, logger.NewStringField("requestId", "req-1"))
+	ctx = logger.ContextWithFields(ctx, logger.NewStringField("workspaceId", "ws-1"))
+	log.WithContext(ctx).Infow("request fields")
+
+	traceID, err := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("0102030405060708")
+	require.NoError(t, err)
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	log.WithContext(ctx).Infon("trace fields", logger.NewIntField("n", 1))
+
+	require.Equal(t, []logger.Field{
+		logger.NewStringField("requestId", "req-1"),
+		logger.NewStringField("workspaceId", "ws-1"),
+	}, logger.FieldsFromContext(ctx))
+	require.Equal(t, logger.NOP, logger.NOP.WithContext(ctx))
+
+	fileOut, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, `INFO	module	request fields	{"requestId": "req-1", "workspaceId": "ws-1"}
+INFO	module	trace fields	{"trace_id": "0102030405060708090a0b0c0d0e0f10", "span_id": "0102030405060708", "requestId": "req-1", "workspaceId": "ws-1", "n": 1}
+`, string(fileOut))
+}
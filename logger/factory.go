@@ -1,13 +1,16 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/khulnasoft/go-kit/config"
 )
@@ -19,13 +22,18 @@ func init() {
 	Default = NewFactory(config.Default)
 }
 
-// Reset resets the default logger factory.
+// Reset resets the default logger factory, closing the previous one.
 // Shall only be used by tests, until we move to a proper DI framework
 func Reset() {
+	previous := Default
 	Default = NewFactory(config.Default)
+	_ = previous.Close()
 }
 
-// NewFactory creates a new logger factory
+// NewFactory creates a new logger factory.
+// Factories observe the config for log level changes and may write to a log file, rotate it and export entries in the
+// background, until they are closed: factories which aren't used for the lifetime of the process should be closed, see
+// Factory.Close.
 func NewFactory(config *config.Config, options ...Option) *Factory {
 	f := &Factory{}
 	f.config = newConfig(config)
@@ -63,9 +71,9 @@ func GetLoggingConfig() map[string]int {
 	return Default.GetLoggingConfig()
 }
 
-// GetLoggingConfig returns the log levels
+// GetLoggingConfig returns a copy of the log levels calculated so far for the loggers in use
 func (f *Factory) GetLoggingConfig() map[string]int {
-	return f.config.levelConfigCache.m
+	return f.config.levelConfigCache.Load().copy()
 }
 
 // SetLogLevel sets the log level for a module for the default logger factory
@@ -93,41 +101,65 @@ func (f *Factory) Sync() {
 	_ = f.sugaredZap.Sync()
 }
 
+// Close flushes the loggers' output buffers and stops the background work of the factory, e.g. the rotation of the
+// log file. Loggers shouldn't be used once their factory is closed.
+func (f *Factory) Close() error {
+	f.Sync()
+	f.config.unregisterObserver()
+	var errs []error
+	for _, c := range f.config.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
 func newConfig(config *config.Config) *factoryConfig {
 	fc := &factoryConfig{
-		levelConfig:      &syncMap[string, int]{m: make(map[string]int)},
-		levelConfigCache: &syncMap[string, int]{m: make(map[string]int)},
+		levelConfig: &syncMap[string, int]{m: make(map[string]int)},
 	}
-	fc.rootLevel = levelMap[config.GetString("LOG_LEVEL", "INFO")]
-	fc.enableNameInLog = config.GetBool("Logger.enableLoggerNameInLog", true)
+	fc.levelConfigCache.Store(newSyncMap[string, int]())
 	fc.enableStackTrace = config.GetReloadableBoolVar(false, "Logger.enableStackTrace")
 	config.GetBool("Logger.enableLoggerNameInLog", true)
 
+	fc.rootLevelConfig = config.GetReloadableStringVar("INFO", "LOG_LEVEL")
 	// colon separated key value pairs
 	// Example: "router.GA=DEBUG:warehouse.REDSHIFT=DEBUG"
-	levelConfigStr := strings.TrimSpace(config.GetString("Logger.moduleLevels", ""))
-	if levelConfigStr != "" {
-		moduleLevelKVs := strings.Split(levelConfigStr, ":")
-		for _, moduleLevelKV := range moduleLevelKVs {
-			pair := strings.SplitN(moduleLevelKV, "=", 2)
-			if len(pair) < 2 {
-				continue
-			}
-			module := strings.TrimSpace(pair[0])
-			if module == "" {
-				continue
-			}
-			levelStr := strings.TrimSpace(pair[1])
-			level, ok := levelMap[levelStr]
-			if !ok {
-				continue
-			}
-			fc.levelConfig.set(module, level)
+	fc.moduleLevelsConfig = config.GetReloadableStringVar("", "Logger.moduleLevels")
+	fc.reload()
+	// the observer keeps the config, thus the factory's state, reachable until the factory is closed, see Reset
+	fc.unregisterObserver = config.RegisterObserver(func(changedKeys []string) {
+		if slices.Contains(changedKeys, "LOG_LEVEL") || slices.Contains(changedKeys, "Logger.moduleLevels") {
+			fc.reload()
 		}
-	}
+	})
 	return fc
 }
 
+// parseModuleLevels parses colon separated module=level pairs, ignoring invalid ones
+func parseModuleLevels(s string) map[string]int {
+	levels := make(map[string]int)
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return levels
+	}
+	for _, moduleLevelKV := range strings.Split(s, ":") {
+		pair := strings.SplitN(moduleLevelKV, "=", 2)
+		if len(pair) < 2 {
+			continue
+		}
+		module := strings.TrimSpace(pair[0])
+		if module == "" {
+			continue
+		}
+		level, ok := levelMap[strings.TrimSpace(pair[1])]
+		if !ok {
+			continue
+		}
+		levels[module] = level
+	}
+	return levels
+}
+
 // newZapLogger configures the zap logger based on the config provide in config.toml
 func newZapLogger(config *config.Config, fc *factoryConfig) *zap.Logger {
 	var cores []zapcore.Core
@@ -137,20 +169,35 @@ func newZapLogger(config *config.Config, fc *factoryConfig) *zap.Logger {
 			writeSyncer = &discarder{}
 		}
 		writer := zapcore.Lock(writeSyncer)
-		core := zapcore.NewCore(zapEncoder(config, config.GetBool("Logger.consoleJsonFormat", false)), writer, zapcore.DebugLevel)
-		cores = append(cores, core)
+		cores = append(cores, newFormatCore(config, config.GetReloadableBoolVar(false, "Logger.consoleJsonFormat"), writer))
 	}
 	if config.GetBool("Logger.enableFile", false) {
-		writer := zapcore.AddSync(&lumberjack.Logger{
-			Filename:  config.GetString("Logger.logFileLocation", "/tmp/rudder_log.log"),
-			MaxSize:   config.GetInt("Logger.logFileSize", 100),
-			Compress:  true,
-			LocalTime: true,
-		})
-		core := zapcore.NewCore(zapEncoder(config, config.GetBool("Logger.fileJsonFormat", false)), writer, zapcore.DebugLevel)
-		cores = append(cores, core)
-	}
-	combinedCore := zapcore.NewTee(cores...)
+		rw := newRotatingWriter(newRotationConfig(config, fc.rotatedFileHandler), time.Now)
+		rw.start()
+		fc.closers = append(fc.closers, rw)
+		writer := zapcore.AddSync(rw)
+		cores = append(cores, newFormatCore(config, config.GetReloadableBoolVar(false, "Logger.fileJsonFormat"), writer))
+	}
+	if config.GetBool("Logger.otlp.enabled", false) {
+		core, err := newOTLPCore(config, fc)
+		if err != nil {
+			reportError(fmt.Errorf("exporting logs over otlp: %w", err))
+		} else {
+			cores = append(cores, core)
+			fc.closers = append(fc.closers, core)
+		}
+	}
+	cores = append(cores, fc.cores...)
+	fc.redactor = newRedactor(newRedactionConfig(config))
+	combinedCore := newRedactingCore(zapcore.NewTee(cores...), fc.redactor)
+	if fc.newCounter != nil {
+		combinedCore = newCountingCore(combinedCore, config.GetReloadableBoolVar(true, "Logger.enableEntryMetrics"), fc.newCounter)
+	}
+	clock := fc.clock
+	if clock == nil {
+		clock = zapcore.DefaultClock
+	}
+	combinedCore = newSamplingCore(combinedCore, newSampler(newSamplingConfig(config), fc.newCounter, clock, fc.moduleLevel))
 	var options []zap.Option
 	if config.GetBool("Logger.enableFileNameInLog", true) {
 		options = append(options, zap.AddCaller(), zap.AddCallerSkip(1))
@@ -177,12 +224,66 @@ func zapEncoder(config *config.Config, json bool) zapcore.Encoder {
 	} else {
 		encoderConfig.TimeKey = ""
 	}
+	if !config.GetBool("Logger.enableLoggerNameInLog", true) {
+		encoderConfig.NameKey = ""
+	}
 	if json {
 		return zapcore.NewJSONEncoder(encoderConfig)
 	}
 	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
+// newFormatCore returns a core writing to the given writer in the JSON or console format, depending on the
+// hot-reloadable json toggle
+func newFormatCore(config *config.Config, json config.ValueLoader[bool], writer zapcore.WriteSyncer) zapcore.Core {
+	return &formatCore{
+		json:        json,
+		jsonCore:    zapcore.NewCore(zapEncoder(config, true), writer, zapcore.DebugLevel),
+		consoleCore: zapcore.NewCore(zapEncoder(config, false), writer, zapcore.DebugLevel),
+	}
+}
+
+// formatCore delegates to either a JSON or a console core, both writing to the same writer
+type formatCore struct {
+	json        config.ValueLoader[bool]
+	jsonCore    zapcore.Core
+	consoleCore zapcore.Core
+}
+
+func (c *formatCore) core() zapcore.Core {
+	if c.json.Load() {
+		return c.jsonCore
+	}
+	return c.consoleCore
+}
+
+func (c *formatCore) Enabled(level zapcore.Level) bool {
+	return c.core().Enabled(level)
+}
+
+func (c *formatCore) With(fields []zapcore.Field) zapcore.Core {
+	return &formatCore{
+		json:        c.json,
+		jsonCore:    c.jsonCore.With(fields),
+		consoleCore: c.consoleCore.With(fields),
+	}
+}
+
+func (c *formatCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *formatCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(entry, fields)
+}
+
+func (c *formatCore) Sync() error {
+	return c.core().Sync()
+}
+
 type discarder struct{}
 
 func (d *discarder) Sync() error                 { return nil }
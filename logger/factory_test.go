@@ -170,3 +170,80 @@ func Test_Config_Suppressed_Logs(t *testing.T) {
 	rootLogger.Errorw("hello world", "key", "value")
 	require.False(t, bufio.NewScanner(f).Scan(), "it should not print a log statement for a level lower than FATAL")
 }
+
+func TestLogLevelHotReload(t *testing.T) {
+	c := config.New()
+	c.Set("LOG_LEVEL", "INFO")
+	c.Set("Logger.moduleLevels", "router=WARN:warehouse=ERROR")
+	c.Set("Logger.discardConsole", true)
+	loggerFactory := logger.NewFactory(c)
+	rootLogger := loggerFactory.NewLogger()
+	gaLogger := rootLogger.Child("router").Child("GA")
+	warehouseLogger := rootLogger.Child("warehouse")
+	otherLogger := rootLogger.Child("other")
+
+	require.False(t, gaLogger.IsDebugLevel())
+	require.False(t, otherLogger.IsDebugLevel())
+	require.Equal(t, 3, loggerFactory.GetLoggingConfig()["router.GA"])
+
+	c.Set("Logger.moduleLevels", "router.GA=DEBUG:warehouse=ERROR")
+	require.True(t, gaLogger.IsDebugLevel())
+	require.Equal(t, map[string]int{"router.GA": 1}, loggerFactory.GetLoggingConfig(), "cache invalidated")
+
+	// levels set at runtime are kept, unless the module changes in the configuration
+	require.NoError(t, loggerFactory.SetLogLevel("warehouse", "DEBUG"))
+	c.Set("Logger.moduleLevels", "warehouse=ERROR")
+	require.True(t, warehouseLogger.IsDebugLevel())
+	require.False(t, gaLogger.IsDebugLevel(), "router.GA removed from the configuration")
+
+	c.Set("LOG_LEVEL", "DEBUG")
+	require.True(t, otherLogger.IsDebugLevel())
+	require.True(t, gaLogger.IsDebugLevel())
+
+	c.Set("LOG_LEVEL", "INVALID")
+	require.True(t, otherLogger.IsDebugLevel(), "invalid levels are ignored")
+	c.Set("LOG_LEVEL", "INFO")
+	require.False(t, otherLogger.IsDebugLevel())
+
+	require.NoError(t, loggerFactory.Close())
+	c.Set("LOG_LEVEL", "DEBUG")
+	require.False(t, otherLogger.IsDebugLevel(), "closed factories stop observing the configuration")
+}
+
+func TestJSONFormatHotReload(t *testing.T) {
+	fileName := t.TempDir() + "out.log"
+	c := config.New()
+	c.Set("Logger.enableTimestamp", false)
+	c.Set("Logger.enableConsole", false)
+	c.Set("Logger.enableFile", true)
+	c.Set("Logger.enableFileNameInLog", false)
+	c.Set("Logger.logFileLocation", fileName)
+	loggerFactory := logger.NewFactory(c)
+	t.Cleanup(func() { _ = loggerFactory.Close() })
+	log := loggerFactory.NewLogger().Child("module").With("key", "value")
+
+	log.Info("console")
+	c.Set("Logger.fileJsonFormat", true)
+	log.Info("json")
+	c.Set("Logger.fileJsonFormat", false)
+	log.Info("console again")
+
+	fileOut, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, `INFO	module	console	{"key": "value"}
+{"level":"INFO","logger":"module","msg":"json","key":"value"}
+INFO	module	console again	{"key": "value"}
+`, string(fileOut))
+}
+
+func TestResetClosesTheDefaultFactory(t *testing.T) {
+	logLevel := config.Default.GetString("LOG_LEVEL", "INFO")
+	t.Cleanup(func() { config.Default.Set("LOG_LEVEL", logLevel) })
+	config.Default.Set("LOG_LEVEL", "INFO")
+	previousLogger := logger.NewLogger()
+
+	logger.Reset()
+	config.Default.Set("LOG_LEVEL", "DEBUG")
+	require.True(t, logger.NewLogger().IsDebugLevel())
+	require.False(t, previousLogger.IsDebugLevel(), "the previous default factory stops observing the configuration")
+}
@@ -17,6 +17,7 @@ const (
 	TimeType
 	DurationType
 	ErrorType
+	SecretType
 )
 
 type Field struct {
@@ -50,6 +51,8 @@ func (f Field) Value() any {
 		return f.duration
 	case ErrorType:
 		return f.error
+	case SecretType:
+		return secret(f.string)
 	default:
 		return f.unknown
 	}
@@ -71,6 +74,8 @@ func (f Field) toZap() zap.Field {
 		return zap.Duration(f.name, f.duration)
 	case ErrorType:
 		return zap.Error(f.error)
+	case SecretType:
+		return zap.Stringer(f.name, secret(f.string))
 	default:
 		return zap.Any(f.name, f.unknown)
 	}
@@ -108,6 +113,12 @@ func NewErrorField(v error) Field {
 	return Field{name: "error", error: v, fieldType: ErrorType}
 }
 
+// NewSecretField creates a field whose value is always masked when logged, or hashed if configured so (see
+// Logger.redaction.hash and Logger.redaction.hashKey)
+func NewSecretField(key, v string) Field {
+	return Field{name: key, string: v, fieldType: SecretType}
+}
+
 // Expand is useful if you want to use the type Field with the sugared logger
 // e.g. l.Infow("my message", logger.Expand(f1, f2, f3)...)
 func Expand(fields ...Field) []any {
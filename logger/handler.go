@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelHandler returns an http.Handler for inspecting and changing the log levels of the default logger factory,
+// see Factory.LevelHandler
+func LevelHandler() http.Handler {
+	return Default.LevelHandler()
+}
+
+// LevelHandler returns an http.Handler for inspecting and changing the log levels of the factory's loggers at runtime.
+//
+// GET returns the root level along with the effective levels of the modules which are either configured or in use, e.g.
+//
+//	{"root":"INFO","modules":{"router":"DEBUG","router.GA":"DEBUG"},"reverts":{"router":"2024-01-01T10:00:00Z"}}
+//
+// PUT and POST set the level of a module, or of the root logger if the module is empty, e.g.
+//
+//	{"module":"router","level":"DEBUG","ttl":"10m"}
+//
+// If a ttl is provided the level reverts to its previous value once it expires, i.e. to the level the module had
+// before the first of any consecutive changes with a ttl. A change without a ttl cancels any pending revert.
+// Reverts are scheduled by the returned handler, thus a single handler should be created per factory.
+func (f *Factory) LevelHandler() http.Handler {
+	return &levelHandler{
+		config:  f.config,
+		logger:  f.NewLogger().Child("logger"),
+		reverts: make(map[string]*levelRevert),
+	}
+}
+
+type levelHandler struct {
+	config *factoryConfig
+	logger Logger
+
+	mu      sync.Mutex
+	reverts map[string]*levelRevert // pending reverts by module, the root logger being ""
+}
+
+// levelRevert is the state a module reverts to once the ttl of its level expires
+type levelRevert struct {
+	level      int
+	configured bool // false if the module had no level configured, i.e. inherited its parent's one
+	at         time.Time
+	timer      *time.Timer
+}
+
+type levelsResponse struct {
+	Root    string               `json:"root"`
+	Modules map[string]string    `json:"modules"`
+	Reverts map[string]time.Time `json:"reverts,omitempty"`
+}
+
+type setLevelRequest struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+	TTL    string `json:"ttl"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req setLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := h.setLevel(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut, http.MethodPost}, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.levels())
+}
+
+func (h *levelHandler) setLevel(req setLevelRequest) error {
+	module := strings.TrimSpace(req.Module)
+	level, ok := levelMap[strings.ToUpper(strings.TrimSpace(req.Level))]
+	if !ok {
+		return fmt.Errorf("invalid level %q", req.Level)
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl < 0 {
+			return fmt.Errorf("invalid ttl %q", req.TTL)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	revert, pending := h.reverts[module]
+	if pending {
+		revert.timer.Stop()
+		delete(h.reverts, module)
+	}
+	if ttl > 0 {
+		if !pending {
+			revert = &levelRevert{level: int(h.config.rootLevel.Load()), configured: true}
+			if module != "" {
+				revert.level, revert.configured = h.config.levelConfig.get(module)
+			}
+		}
+		// a copy, so that the timer of a superseded revert can't revert a newer change
+		revert = &levelRevert{level: revert.level, configured: revert.configured, at: time.Now().Add(ttl)}
+		revert.timer = time.AfterFunc(ttl, func() { h.revert(module, revert) })
+		h.reverts[module] = revert
+	}
+	h.config.setLogLevel(module, level)
+	h.logger.Infow("Log level changed", "module", module, "level", levelName(level), "ttl", ttl)
+	return nil
+}
+
+func (h *levelHandler) revert(module string, revert *levelRevert) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.reverts[module] != revert {
+		return // superseded by a newer change
+	}
+	delete(h.reverts, module)
+	if revert.configured {
+		h.config.setLogLevel(module, revert.level)
+	} else {
+		h.config.unsetLogLevel(module)
+	}
+	h.logger.Infow("Log level reverted", "module", module)
+}
+
+func (h *levelHandler) levels() levelsResponse {
+	res := levelsResponse{
+		Root:    levelName(int(h.config.rootLevel.Load())),
+		Modules: make(map[string]string),
+	}
+	for module, level := range h.config.levelConfigCache.Load().copy() {
+		res.Modules[module] = levelName(level)
+	}
+	for module, level := range h.config.levelConfig.copy() {
+		res.Modules[module] = levelName(level)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.reverts) > 0 {
+		res.Reverts = make(map[string]time.Time, len(h.reverts))
+		for module, revert := range h.reverts {
+			res.Reverts[module] = revert.at
+		}
+	}
+	return res
+}
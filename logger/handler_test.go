@@ -0,0 +1,156 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+type levelsResponse struct {
+	Root    string               `json:"root"`
+	Modules map[string]string    `json:"modules"`
+	Reverts map[string]time.Time `json:"reverts"`
+}
+
+func TestLevelHandler(t *testing.T) {
+	c := config.New()
+	c.Set("LOG_LEVEL", "INFO")
+	c.Set("Logger.moduleLevels", "router=WARN")
+	c.Set("Logger.discardConsole", true)
+	loggerFactory := logger.NewFactory(c)
+	handler := loggerFactory.LevelHandler()
+	rootLogger := loggerFactory.NewLogger()
+	routerLogger := rootLogger.Child("router")
+	gaLogger := routerLogger.Child("GA")
+	otherLogger := rootLogger.Child("other")
+
+	do := func(t *testing.T, method, body string) (int, levelsResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		var res levelsResponse
+		if rec.Code == http.StatusOK {
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		}
+		return rec.Code, res
+	}
+
+	require.False(t, gaLogger.IsDebugLevel())
+	require.False(t, otherLogger.IsDebugLevel())
+	code, res := do(t, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, levelsResponse{Root: "INFO", Modules: map[string]string{
+		"router": "WARN", "router.GA": "WARN", "other": "INFO",
+	}}, res)
+
+	t.Run("set module level", func(t *testing.T) {
+		code, res := do(t, http.MethodPut, `{"module":"router","level":"debug"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "DEBUG", res.Modules["router"])
+		require.True(t, gaLogger.IsDebugLevel(), "children inherit the new level")
+		require.False(t, otherLogger.IsDebugLevel())
+	})
+
+	t.Run("set root level", func(t *testing.T) {
+		code, res := do(t, http.MethodPost, `{"level":"DEBUG"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "DEBUG", res.Root)
+		require.True(t, otherLogger.IsDebugLevel())
+
+		code, _ = do(t, http.MethodPost, `{"level":"INFO"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.False(t, otherLogger.IsDebugLevel())
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		code, res := do(t, http.MethodPut, `{"module":"other","level":"DEBUG","ttl":"100ms"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, res.Reverts, "other")
+		require.True(t, otherLogger.IsDebugLevel())
+
+		// a second change with a ttl reverts to the level before the first one
+		code, _ = do(t, http.MethodPut, `{"module":"other","level":"WARN","ttl":"200ms"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.False(t, otherLogger.IsDebugLevel())
+
+		require.Eventually(t, func() bool {
+			_, res := do(t, http.MethodGet, "")
+			return len(res.Reverts) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		require.False(t, otherLogger.IsDebugLevel())
+		_, res = do(t, http.MethodGet, "")
+		require.Equal(t, "INFO", res.Modules["other"], "level inherited from root again")
+
+		// a change without a ttl cancels the revert
+		code, _ = do(t, http.MethodPut, `{"module":"router","level":"ERROR","ttl":"50ms"}`)
+		require.Equal(t, http.StatusOK, code)
+		code, res = do(t, http.MethodPut, `{"module":"router","level":"WARN"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, res.Reverts)
+		time.Sleep(100 * time.Millisecond)
+		_, res = do(t, http.MethodGet, "")
+		require.Equal(t, "WARN", res.Modules["router"])
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		code, _ := do(t, http.MethodPut, `{"module":"router","level":"VERBOSE"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		code, _ = do(t, http.MethodPut, `{"module":"router","level":"INFO","ttl":"-1s"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		code, _ = do(t, http.MethodPut, `{`)
+		require.Equal(t, http.StatusBadRequest, code)
+		code, _ = do(t, http.MethodDelete, "")
+		require.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+}
+
+func TestLevelHandlerConcurrently(t *testing.T) {
+	c := config.New()
+	c.Set("Logger.discardConsole", true)
+	loggerFactory := logger.NewFactory(c)
+	handler := loggerFactory.LevelHandler()
+	log := loggerFactory.NewLogger().Child("module").Child("child")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.Debugw("message", "j", j)
+				_ = loggerFactory.GetLoggingConfig()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				body := `{"module":"module","level":"DEBUG","ttl":"1ms"}`
+				if j%2 == 0 {
+					body = `{"level":"WARN"}`
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)))
+				require.Equal(t, http.StatusOK, rec.Code)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+				require.Equal(t, http.StatusOK, rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -17,3 +17,13 @@ var levelMap = map[string]int{
 	"ERROR": levelError,
 	"FATAL": levelFatal,
 }
+
+// levelName returns the name of the given level, e.g. "INFO"
+func levelName(level int) string {
+	for name, l := range levelMap {
+		if l == level {
+			return name
+		}
+	}
+	return ""
+}
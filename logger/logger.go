@@ -28,12 +28,14 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"runtime"
 	"strings"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 /*
@@ -127,8 +129,16 @@ type Logger interface {
 
 	// Withn adds the provided key value pairs to the logger context
 	Withn(args ...Field) Logger
+
+	// WithContext returns a logger with the ids of the trace and span active in the context (see TraceIDKey and
+	// SpanIDKey), if any, and the request-scoped fields held by the context (see ContextWithFields)
+	WithContext(ctx context.Context) Logger
 }
 
+// fatalMarker marks the entries logged by the Fatal methods, which are logged with the ERROR level, e.g. for a TestFactory
+// to tell them apart. It is never encoded, being of the skip type.
+var fatalMarker = zapcore.Field{Key: "logger.fatal", Type: zapcore.SkipType}
+
 type logger struct {
 	logConfig  *factoryConfig
 	name       string
@@ -148,10 +158,9 @@ func (l *logger) Child(s string) Logger {
 	} else {
 		cp.name = strings.Join([]string{l.name, s}, ".")
 	}
-	if l.logConfig.enableNameInLog {
-		cp.sugaredZap = l.sugaredZap.Named(s)
-		cp.zap = l.zap.Named(s)
-	}
+	// loggers are always named, for sampling purposes, names being omitted from the output if disabled (see zapEncoder)
+	cp.sugaredZap = l.sugaredZap.Named(s)
+	cp.zap = l.zap.Named(s)
 	return &cp
 }
 
@@ -183,6 +192,24 @@ func (l *logger) Withn(args ...Field) Logger {
 	return &cp
 }
 
+// WithContext adds the ids of the trace and span active in the context, if any, and the request-scoped fields
+// held by the context to the logging context.
+func (l *logger) WithContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	zapFields := toZap(fields)
+	args := make([]any, len(zapFields))
+	for i := range zapFields {
+		args[i] = zapFields[i]
+	}
+	cp := *l
+	cp.zap = l.zap.With(zapFields...)
+	cp.sugaredZap = l.sugaredZap.With(args...)
+	return &cp
+}
+
 func (l *logger) getLoggingLevel() int {
 	return l.logConfig.getOrSetLogLevel(l.name, l.parent.getLoggingLevel)
 }
@@ -227,7 +254,7 @@ func (l *logger) Error(args ...any) {
 // Fatal level logging.
 // Use this to log errors which crash the application.
 func (l *logger) Fatal(args ...any) {
-	l.sugaredZap.Error(args...)
+	l.sugaredZap.With(fatalMarker).Error(args...)
 
 	// If enableStackTrace is true, Zaplogger will take care of writing stacktrace to the file.
 	// Else, we are force writing the stacktrace to the file.
@@ -275,7 +302,7 @@ func (l *logger) Errorf(format string, args ...any) {
 // Fatalf does fatal level logging similar to fmt.Printf.
 // Use this to log errors which crash the application.
 func (l *logger) Fatalf(format string, args ...any) {
-	l.sugaredZap.Errorf(format, args...)
+	l.sugaredZap.With(fatalMarker).Errorf(format, args...)
 
 	// If enableStackTrace is true, Zaplogger will take care of writing stacktrace to the file.
 	// Else, we are force writing the stacktrace to the file.
@@ -323,7 +350,7 @@ func (l *logger) Errorw(msg string, keysAndValues ...any) {
 // Fatalw does fatal level structured logging.
 // Use this to log errors which crash the application.
 func (l *logger) Fatalw(msg string, keysAndValues ...any) {
-	l.sugaredZap.Errorw(msg, keysAndValues...)
+	l.sugaredZap.With(fatalMarker).Errorw(msg, keysAndValues...)
 
 	// If enableStackTrace is true, Zaplogger will take care of writing stacktrace to the file.
 	// Else, we are force writing the stacktrace to the file.
@@ -372,7 +399,7 @@ func (l *logger) Errorn(msg string, fields ...Field) {
 // Use this to log errors which crash the application.
 func (l *logger) Fataln(msg string, fields ...Field) {
 	zapFields := toZap(fields)
-	l.zap.Error(msg, zapFields...)
+	l.zap.Error(msg, append(zapFields, fatalMarker)...)
 
 	// If enableStackTrace is true, Zaplogger will take care of writing stacktrace to the file.
 	// Else, we are force writing the stacktrace to the file.
@@ -385,7 +412,8 @@ func (l *logger) Fataln(msg string, fields ...Field) {
 	_ = l.zap.Sync()
 }
 
-// LogRequest reads and logs the request body and resets the body to original state.
+// LogRequest reads and logs the request body along with the request headers, sensitive ones (e.g. Authorization and
+// Cookie) being redacted, and resets the body to original state.
 func (l *logger) LogRequest(req *http.Request) {
 	if levelEvent >= l.getLoggingLevel() {
 		defer func() { _ = req.Body.Close() }()
@@ -393,6 +421,10 @@ func (l *logger) LogRequest(req *http.Request) {
 		bodyString := string(bodyBytes)
 		req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 		// print raw request body for debugging purposes
-		l.zap.Debug("Request Body", zap.String("body", bodyString))
+		fields := []zap.Field{zap.String("body", bodyString)}
+		if len(req.Header) > 0 {
+			fields = append(fields, zap.Any("headers", redactHeaders(req.Header, l.logConfig.redactor.redactValue)))
+		}
+		l.zap.Debug("Request Body", fields...)
 	}
 }
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+// entriesMetric is the name of the counter of the entries logged, by level and module
+const entriesMetric = "logger_entries"
+
+// countingCore is a zapcore.Core counting the entries it writes by level and module, i.e. logger name.
+// Entries below the level of their module never reach the core, thus aren't counted and don't add any overhead,
+// while entries suppressed by sampling are counted separately by the sampler.
+type countingCore struct {
+	zapcore.Core
+	enabled  config.ValueLoader[bool]
+	counters *entryCounters
+}
+
+func newCountingCore(core zapcore.Core, enabled config.ValueLoader[bool], newCounter func(name string, tags map[string]string) Counter) zapcore.Core {
+	return &countingCore{Core: core, enabled: enabled, counters: &entryCounters{newCounter: newCounter}}
+}
+
+func (c *countingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &countingCore{Core: c.Core.With(fields), enabled: c.enabled, counters: c.counters}
+}
+
+func (c *countingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *countingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if c.enabled.Load() {
+		c.counters.get(entry).Count(1)
+	}
+	return c.Core.Write(entry, fields)
+}
+
+// entryCounters are the counters of the entries by level and module, created lazily
+type entryCounters struct {
+	newCounter func(name string, tags map[string]string) Counter
+	levels     [zapcore.FatalLevel - zapcore.DebugLevel + 1]struct {
+		mu       sync.RWMutex
+		counters map[string]Counter // by module
+	}
+}
+
+func (ec *entryCounters) get(entry zapcore.Entry) Counter {
+	level := min(max(entry.Level, zapcore.DebugLevel), zapcore.FatalLevel)
+	l := &ec.levels[level-zapcore.DebugLevel]
+	l.mu.RLock()
+	counter, ok := l.counters[entry.LoggerName]
+	l.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if counter, ok := l.counters[entry.LoggerName]; ok {
+		return counter
+	}
+	if l.counters == nil {
+		l.counters = make(map[string]Counter)
+	}
+	counter = ec.newCounter(entriesMetric, map[string]string{"module": entry.LoggerName, "level": level.CapitalString()})
+	l.counters[entry.LoggerName] = counter
+	return counter
+}
@@ -0,0 +1,44 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestEntryMetrics(t *testing.T) {
+	c := config.New()
+	c.Set("LOG_LEVEL", "INFO")
+	c.Set("Logger.discardConsole", true)
+	c.Set("Logger.sampling.enabled", true)
+	c.Set("Logger.sampling.first", 2)
+	c.Set("Logger.sampling.thereafter", 0)
+	counters := &mockCounters{counts: make(map[string]int)}
+	loggerFactory := logger.NewFactory(c, logger.WithCounters(counters.new))
+	root := loggerFactory.NewLogger()
+	router := root.Child("router").With("key", "value")
+
+	root.Debug("disabled level")
+	root.Info("info")
+	router.Infon("info")
+	for i := 0; i < 3; i++ {
+		router.Warn("warn")
+		router.Error("error")
+	}
+	router.Fatal("fatal")
+
+	require.Equal(t, map[string]int{
+		"logger_entries,level=INFO,module=":                  1,
+		"logger_entries,level=INFO,module=router":            1,
+		"logger_entries,level=WARN,module=router":            2,
+		"logger_entries,level=ERROR,module=router":           5, // errors aren't sampled, fatal entries being logged as errors
+		"logger_suppressed_entries,level=WARN,module=router": 1,
+	}, counters.get())
+
+	c.Set("Logger.enableEntryMetrics", false)
+	router.Info("not counted")
+	require.Equal(t, 1, counters.get()["logger_entries,level=INFO,module=router"])
+}
@@ -10,6 +10,7 @@
 package mock_logger
 
 import (
+	context "context"
 	http "net/http"
 	reflect "reflect"
 
@@ -433,6 +434,20 @@ func (mr *MockLoggerMockRecorder) With(arg0 ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockLogger)(nil).With), arg0...)
 }
 
+// WithContext mocks base method.
+func (m *MockLogger) WithContext(arg0 context.Context) logger.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", arg0)
+	ret0, _ := ret[0].(logger.Logger)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockLoggerMockRecorder) WithContext(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockLogger)(nil).WithContext), arg0)
+}
+
 // Withn mocks base method.
 func (m *MockLogger) Withn(arg0 ...logger.Field) logger.Logger {
 	m.ctrl.T.Helper()
@@ -21,3 +21,44 @@ func WithClock(clock zapcore.Clock) Option {
 		factory.config.clock = clock
 	})
 }
+
+// WithCounters specifies how the counters reported by the logger are created, i.e. the counters of the entries logged
+// (logger_entries, unless Logger.enableEntryMetrics is false) and suppressed by sampling (logger_suppressed_entries),
+// both tagged by module and level, as well as the entries which couldn't be exported over OTLP
+// (logger_otlp_dropped_entries), tagged by reason. Counters aren't reported by default. Example:
+//
+//	logger.WithCounters(func(name string, tags map[string]string) logger.Counter {
+//		return stats.Default.NewTaggedStat(name, stats.CountType, tags)
+//	})
+func WithCounters(newCounter func(name string, tags map[string]string) Counter) Option {
+	return optionFunc(func(factory *Factory) {
+		factory.config.newCounter = newCounter
+	})
+}
+
+// WithRotatedFileHandler specifies a handler for the log files once rotated and compressed, e.g. for uploading them
+// to a remote storage with filemanager.RotatedLogFileUploader. Files are deleted locally once handled successfully,
+// while the ones which couldn't be handled are retried on the next rotation, unless deleted by the retention settings
+// (Logger.logFileMaxAge and Logger.logFileMaxBackups) meanwhile.
+func WithRotatedFileHandler(handler RotatedFileHandler) Option {
+	return optionFunc(func(factory *Factory) {
+		factory.config.rotatedFileHandler = handler
+	})
+}
+
+// WithServiceName specifies the name of the service, set as the service.name attribute of the resource log records are
+// exported with over OTLP (see Logger.otlp.enabled), like stats.WithServiceName does for metrics and traces.
+func WithServiceName(name string) Option {
+	return optionFunc(func(factory *Factory) {
+		factory.config.serviceName = name
+	})
+}
+
+// WithServiceVersion specifies the version of the service, set as the service.version attribute of the resource log
+// records are exported with over OTLP (see Logger.otlp.enabled), like stats.WithServiceVersion does for metrics and
+// traces.
+func WithServiceVersion(version string) Option {
+	return optionFunc(func(factory *Factory) {
+		factory.config.serviceVersion = version
+	})
+}
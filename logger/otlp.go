@@ -0,0 +1,672 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+// droppedEntriesMetric is the name of the counter of the entries which couldn't be exported over OTLP, by reason:
+// queue_full if the export queue was full, export_failed if the collector rejected them or couldn't be reached
+const droppedEntriesMetric = "logger_otlp_dropped_entries"
+
+// otlpConfig is the configuration of the export of the log entries over OTLP
+type otlpConfig struct {
+	endpoint       string // host:port of the collector
+	protocol       string // grpc or http
+	urlPath        string // the path log records are posted to, for the http protocol
+	insecure       bool
+	queueSize      int // the number of entries waiting to be exported, newer entries being dropped once reached
+	batchSize      int // the maximum number of entries exported at once
+	exportInterval time.Duration
+	exportTimeout  time.Duration // the timeout of each export attempt, and of the last export on close
+	syncTimeout    time.Duration // the time Sync waits for the queued records to be exported, kept short for Logger.Fatal
+
+	retryEnabled         bool
+	retryInitialInterval time.Duration
+	retryMaxInterval     time.Duration
+	retryMaxElapsedTime  time.Duration // the time after which failing exports are given up, 0 for retrying forever
+}
+
+func newOTLPConfig(c *config.Config) otlpConfig {
+	oc := otlpConfig{
+		protocol:             strings.ToLower(c.GetString("Logger.otlp.protocol", "grpc")),
+		urlPath:              c.GetString("Logger.otlp.urlPath", "/v1/logs"),
+		insecure:             c.GetBool("Logger.otlp.insecure", true), // like the exporters of stats
+		queueSize:            max(c.GetInt("Logger.otlp.queueSize", 2048), 1),
+		batchSize:            max(c.GetInt("Logger.otlp.batchSize", 512), 1),
+		exportInterval:       c.GetDuration("Logger.otlp.exportInterval", 1, time.Second),
+		exportTimeout:        c.GetDuration("Logger.otlp.exportTimeout", 30, time.Second),
+		syncTimeout:          c.GetDuration("Logger.otlp.syncTimeout", 10, time.Millisecond),
+		retryEnabled:         c.GetBool("Logger.otlp.retry.enabled", true),
+		retryInitialInterval: c.GetDuration("Logger.otlp.retry.initialInterval", 5, time.Second),
+		retryMaxInterval:     c.GetDuration("Logger.otlp.retry.maxInterval", 30, time.Second),
+		retryMaxElapsedTime:  c.GetDuration("Logger.otlp.retry.maxElapsedTime", 1, time.Minute),
+	}
+	if oc.protocol != "http" {
+		oc.protocol = "grpc"
+	}
+	defaultEndpoint := "localhost:4317"
+	if oc.protocol == "http" {
+		defaultEndpoint = "localhost:4318"
+	}
+	oc.endpoint = c.GetString("Logger.otlp.endpoint", defaultEndpoint)
+	if oc.exportInterval <= 0 {
+		oc.exportInterval = time.Second
+	}
+	return oc
+}
+
+// newOTLPResource returns the resource log records are exported with, having the same attributes as the resource of
+// the metrics and traces exported by stats: the service name and version, along with the instance name and namespace
+func newOTLPResource(c *config.Config, serviceName, serviceVersion string) (*resource.Resource, error) {
+	var attrs []attribute.KeyValue
+	if serviceName != "" {
+		attrs = append(attrs, semconv.ServiceNameKey.String(serviceName))
+	}
+	if serviceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(serviceVersion))
+	}
+	if instanceName := c.GetString("INSTANCE_ID", ""); instanceName != "" {
+		attrs = append(attrs, attribute.String("instanceName", instanceName))
+	}
+	if namespace := os.Getenv("KUBE_NAMESPACE"); namespace != "" {
+		attrs = append(attrs, attribute.String("namespace", namespace))
+	}
+	return resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+}
+
+// newOTLPCore returns a core exporting the entries it writes over OTLP, in batches, in the background, until it is
+// closed
+func newOTLPCore(c *config.Config, fc *factoryConfig) (*otlpCore, error) {
+	oc := newOTLPConfig(c)
+	res, err := newOTLPResource(c, fc.serviceName, fc.serviceVersion)
+	if err != nil {
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+	var client otlpClient
+	if oc.protocol == "http" {
+		client = newOTLPHTTPClient(oc)
+	} else if client, err = newOTLPGRPCClient(oc); err != nil {
+		return nil, fmt.Errorf("creating grpc client: %w", err)
+	}
+
+	e := &otlpExporter{
+		config: oc,
+		client: client,
+		resource: &resourcepb.Resource{
+			Attributes: attributesToProto(res.Attributes()),
+		},
+		schemaURL: res.SchemaURL(),
+		queue:     make(chan otlpRecord, oc.queueSize),
+		flushes:   make(chan chan struct{}),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if fc.newCounter != nil {
+		e.queueFull = fc.newCounter(droppedEntriesMetric, map[string]string{"reason": "queue_full"})
+		e.exportFailed = fc.newCounter(droppedEntriesMetric, map[string]string{"reason": "export_failed"})
+	}
+	go e.run()
+	return &otlpCore{exporter: e}, nil
+}
+
+// otlpCore is a zapcore.Core converting the entries it writes to OTLP log records, passed to the exporter.
+// The logger name is the instrumentation scope of the records, while the ids of the trace and span added by
+// Logger.WithContext are set as the records' ones.
+type otlpCore struct {
+	fields   []zapcore.Field
+	exporter *otlpExporter
+}
+
+func (*otlpCore) Enabled(zapcore.Level) bool {
+	return true // levels are checked by the logger
+}
+
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	res := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	res = append(res, c.fields...)
+	return &otlpCore{fields: append(res, fields...), exporter: c.exporter}
+}
+
+func (c *otlpCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+func (c *otlpCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	c.exporter.enqueue(otlpRecord{scope: entry.LoggerName, record: newLogRecord(entry, c.fields, fields)})
+	return nil
+}
+
+// Sync exports the entries written so far, see otlpExporter.flush
+func (c *otlpCore) Sync() error {
+	return c.exporter.flush()
+}
+
+// Close stops the exporter, see otlpExporter.close
+func (c *otlpCore) Close() error {
+	return c.exporter.close()
+}
+
+// newLogRecord converts the entry and its fields to an OTLP log record
+func newLogRecord(entry zapcore.Entry, fieldSets ...[]zapcore.Field) *logspb.LogRecord {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, fields := range fieldSets {
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+	}
+	record := &logspb.LogRecord{
+		TimeUnixNano:         uint64(entry.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+		SeverityNumber:       severityNumber(entry.Level),
+		SeverityText:         entry.Level.CapitalString(),
+		Body:                 anyValue(entry.Message),
+	}
+	if s, ok := enc.Fields[TraceIDKey].(string); ok {
+		if id, err := trace.TraceIDFromHex(s); err == nil {
+			record.TraceId = id[:]
+			delete(enc.Fields, TraceIDKey)
+		}
+	}
+	if s, ok := enc.Fields[SpanIDKey].(string); ok {
+		if id, err := trace.SpanIDFromHex(s); err == nil {
+			record.SpanId = id[:]
+			delete(enc.Fields, SpanIDKey)
+		}
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	record.Attributes = make([]*commonpb.KeyValue, 0, len(keys)+4)
+	for _, k := range keys {
+		record.Attributes = append(record.Attributes, &commonpb.KeyValue{Key: k, Value: anyValue(enc.Fields[k])})
+	}
+	if entry.Caller.Defined {
+		record.Attributes = append(record.Attributes,
+			&commonpb.KeyValue{Key: string(semconv.CodeFilepathKey), Value: anyValue(entry.Caller.File)},
+			&commonpb.KeyValue{Key: string(semconv.CodeLineNumberKey), Value: anyValue(entry.Caller.Line)},
+		)
+		if entry.Caller.Function != "" {
+			record.Attributes = append(record.Attributes, &commonpb.KeyValue{Key: string(semconv.CodeFunctionKey), Value: anyValue(entry.Caller.Function)})
+		}
+	}
+	if entry.Stack != "" {
+		record.Attributes = append(record.Attributes, &commonpb.KeyValue{Key: string(semconv.CodeStacktraceKey), Value: anyValue(entry.Stack)})
+	}
+	return record
+}
+
+func severityNumber(level zapcore.Level) logspb.SeverityNumber {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case level == zapcore.InfoLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case level == zapcore.WarnLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case level == zapcore.ErrorLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default: // DPanic, Panic and Fatal
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	}
+}
+
+// anyValue converts a value encoded by a zapcore.MapObjectEncoder to an OTLP value
+func anyValue(v any) *commonpb.AnyValue {
+	switch v := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int8:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int16:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}}
+	case uint:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case uint8:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case uint16:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case uint32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case uint64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v}}
+	case time.Time:
+		return anyValue(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return anyValue(v.String())
+	case []any:
+		values := make([]*commonpb.AnyValue, 0, len(v))
+		for _, e := range v {
+			values = append(values, anyValue(e))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]*commonpb.KeyValue, 0, len(v))
+		for _, k := range keys {
+			values = append(values, &commonpb.KeyValue{Key: k, Value: anyValue(v[k])})
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: values}}}
+	case error:
+		return anyValue(v.Error())
+	case fmt.Stringer:
+		return anyValue(v.String())
+	default: // reflected values
+		if b, err := json.Marshal(v); err == nil {
+			return anyValue(string(b))
+		}
+		return anyValue(fmt.Sprint(v))
+	}
+}
+
+func attributesToProto(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	res := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		var v *commonpb.AnyValue
+		switch attr.Value.Type() {
+		case attribute.BOOL:
+			v = anyValue(attr.Value.AsBool())
+		case attribute.INT64:
+			v = anyValue(attr.Value.AsInt64())
+		case attribute.FLOAT64:
+			v = anyValue(attr.Value.AsFloat64())
+		default:
+			v = anyValue(attr.Value.Emit())
+		}
+		res = append(res, &commonpb.KeyValue{Key: string(attr.Key), Value: v})
+	}
+	return res
+}
+
+// otlpRecord is a log record waiting to be exported, along with its instrumentation scope
+type otlpRecord struct {
+	scope  string
+	record *logspb.LogRecord
+}
+
+// otlpExporter exports log records in batches, either when a batch is full or at the export interval, retrying
+// failed exports with an exponential backoff, or the delay requested by the collector if any.
+// Records are queued so that logging never blocks: newer records are dropped while the queue is full, e.g. while the
+// collector is unavailable, and counted as such.
+type otlpExporter struct {
+	config    otlpConfig
+	client    otlpClient
+	resource  *resourcepb.Resource
+	schemaURL string
+
+	queue    chan otlpRecord
+	flushes  chan chan struct{}
+	retrying atomic.Bool // whether a failed export is being retried
+
+	stop      chan struct{} // closed by close, for run to export the remaining records and return
+	done      chan struct{} // closed once run returned
+	closeOnce sync.Once
+	closeErr  error
+
+	dropped      atomic.Int64 // the records dropped since last reported, the queue being full
+	queueFull    Counter
+	exportFailed Counter
+}
+
+func (e *otlpExporter) enqueue(r otlpRecord) {
+	select {
+	case e.queue <- r:
+	default:
+		e.dropped.Add(1)
+		if e.queueFull != nil {
+			e.queueFull.Count(1)
+		}
+	}
+}
+
+// flush waits for the records queued so far to be exported, for up to the sync timeout. It returns right away while a
+// failed export is being retried or once the exporter is closed, so that syncing never blocks on the collector.
+func (e *otlpExporter) flush() error {
+	if e.retrying.Load() {
+		return nil
+	}
+	done := make(chan struct{})
+	timer := time.NewTimer(e.config.syncTimeout)
+	defer timer.Stop()
+	select {
+	case e.flushes <- done:
+	case <-e.done:
+		return nil
+	case <-timer.C:
+		return errors.New("flushing otlp log records: timed out")
+	}
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return errors.New("flushing otlp log records: timed out")
+	}
+}
+
+// close stops the exporter, waiting for up to the export timeout for the records queued so far to be exported without
+// retrying, and closes the client
+func (e *otlpExporter) close() error {
+	e.closeOnce.Do(func() {
+		close(e.stop)
+		timer := time.NewTimer(e.config.exportTimeout)
+		defer timer.Stop()
+		select {
+		case <-e.done:
+		case <-timer.C:
+			reportError(errors.New("closing otlp exporter: timed out exporting the remaining log records"))
+		}
+		e.closeErr = e.client.close()
+	})
+	return e.closeErr
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.config.exportInterval)
+	defer ticker.Stop()
+	batch := make([]otlpRecord, 0, e.config.batchSize)
+	add := func(r otlpRecord) {
+		if batch = append(batch, r); len(batch) >= e.config.batchSize {
+			batch = e.export(batch)
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case r := <-e.queue:
+				add(r)
+			default:
+				batch = e.export(batch)
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case r := <-e.queue:
+			add(r)
+		case <-ticker.C:
+			batch = e.export(batch)
+		case done := <-e.flushes:
+			drain()
+			close(done)
+		case <-e.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// export exports the batch, returning it emptied
+func (e *otlpExporter) export(batch []otlpRecord) []otlpRecord {
+	if dropped := e.dropped.Swap(0); dropped > 0 {
+		reportError(fmt.Errorf("dropped %d log records, the otlp export queue being full", dropped))
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+	err := e.send(e.newRequest(batch))
+	var rejected *rejectedError
+	switch {
+	case errors.As(err, &rejected):
+		e.countFailed(int(rejected.rejected))
+		reportError(fmt.Errorf("exporting log records over otlp: %w", err))
+	case err != nil:
+		e.countFailed(len(batch))
+		reportError(fmt.Errorf("exporting %d log records over otlp: %w", len(batch), err))
+	}
+	clear(batch)
+	return batch[:0]
+}
+
+func (e *otlpExporter) countFailed(n int) {
+	if e.exportFailed != nil && n > 0 {
+		e.exportFailed.Count(n)
+	}
+}
+
+// newRequest groups the records of the batch by scope, in the order they were logged
+func (e *otlpExporter) newRequest(batch []otlpRecord) *collogspb.ExportLogsServiceRequest {
+	var scopeLogs []*logspb.ScopeLogs
+	byScope := make(map[string]*logspb.ScopeLogs)
+	for _, r := range batch {
+		sl, ok := byScope[r.scope]
+		if !ok {
+			sl = &logspb.ScopeLogs{Scope: &commonpb.InstrumentationScope{Name: r.scope}}
+			byScope[r.scope] = sl
+			scopeLogs = append(scopeLogs, sl)
+		}
+		sl.LogRecords = append(sl.LogRecords, r.record)
+	}
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{Resource: e.resource, ScopeLogs: scopeLogs, SchemaUrl: e.schemaURL}},
+	}
+}
+
+// send sends the request, retrying on retryable errors until the exporter is closed
+func (e *otlpExporter) send(req *collogspb.ExportLogsServiceRequest) error {
+	defer e.retrying.Store(false)
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = e.config.retryInitialInterval
+	bo.MaxInterval = e.config.retryMaxInterval
+	bo.MaxElapsedTime = e.config.retryMaxElapsedTime
+	bo.Reset()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), e.config.exportTimeout)
+		err := e.client.export(ctx, req)
+		cancel()
+		var retryable *retryableError
+		if err == nil || !e.config.retryEnabled || !errors.As(err, &retryable) {
+			return err
+		}
+		delay := bo.NextBackOff()
+		if delay == backoff.Stop {
+			return err
+		}
+		if retryable.throttle > delay {
+			if bo.MaxElapsedTime > 0 && bo.GetElapsedTime()+retryable.throttle > bo.MaxElapsedTime {
+				return err
+			}
+			delay = retryable.throttle
+		}
+		e.retrying.Store(true)
+		timer := time.NewTimer(delay)
+		select {
+		case <-e.stop:
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// otlpClient sends export requests to a collector
+type otlpClient interface {
+	export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error
+	close() error
+}
+
+// retryableError is an export error which can be retried, after the delay requested by the collector, if any
+type retryableError struct {
+	err      error
+	throttle time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// rejectedError is returned when the collector rejected part of the records, which isn't retried
+type rejectedError struct {
+	rejected int64
+	message  string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("%d log records rejected: %s", e.rejected, e.message)
+}
+
+func partialSuccessError(ps *collogspb.ExportLogsPartialSuccess) error {
+	if ps.GetRejectedLogRecords() == 0 {
+		return nil
+	}
+	return &rejectedError{rejected: ps.GetRejectedLogRecords(), message: ps.GetErrorMessage()}
+}
+
+type otlpGRPCClient struct {
+	conn   *grpc.ClientConn
+	client collogspb.LogsServiceClient
+}
+
+func newOTLPGRPCClient(config otlpConfig) (*otlpGRPCClient, error) {
+	creds := credentials.NewTLS(nil)
+	if config.insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(config.endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	return &otlpGRPCClient{conn: conn, client: collogspb.NewLogsServiceClient(conn)}, nil
+}
+
+func (c *otlpGRPCClient) export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	res, err := c.client.Export(ctx, req)
+	if err != nil {
+		return grpcError(err)
+	}
+	return partialSuccessError(res.GetPartialSuccess())
+}
+
+func (c *otlpGRPCClient) close() error {
+	return c.conn.Close()
+}
+
+// grpcError wraps the errors which can be retried according to the OTLP specification in a retryableError
+func grpcError(err error) error {
+	s := status.Convert(err)
+	var throttle time.Duration
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			throttle = info.GetRetryDelay().AsDuration()
+		}
+	}
+	switch s.Code() {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+		return &retryableError{err: err, throttle: throttle}
+	case codes.ResourceExhausted:
+		if throttle > 0 { // the collector can recover
+			return &retryableError{err: err, throttle: throttle}
+		}
+	}
+	return err
+}
+
+type otlpHTTPClient struct {
+	client *http.Client
+	url    string
+}
+
+func newOTLPHTTPClient(config otlpConfig) *otlpHTTPClient {
+	scheme := "https"
+	if config.insecure {
+		scheme = "http"
+	}
+	return &otlpHTTPClient{client: &http.Client{}, url: scheme + "://" + config.endpoint + config.urlPath}
+}
+
+func (c *otlpHTTPClient) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func (c *otlpHTTPClient) export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return &retryableError{err: err}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &retryableError{err: err}
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		var res collogspb.ExportLogsServiceResponse
+		if err := proto.Unmarshal(respBody, &res); err != nil {
+			return nil // the records were accepted anyway
+		}
+		return partialSuccessError(res.GetPartialSuccess())
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+		var throttle time.Duration
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			throttle = time.Duration(seconds) * time.Second
+		}
+		return &retryableError{err: fmt.Errorf("unexpected status: %s", resp.Status), throttle: throttle}
+	default:
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+}
@@ -0,0 +1,306 @@
+package logger_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestOTLP(t *testing.T) {
+	newFactory := func(t *testing.T, protocol, endpoint string, options ...logger.Option) (*config.Config, *logger.Factory) {
+		t.Setenv("KUBE_NAMESPACE", "namespace")
+		c := config.New()
+		c.Set("INSTANCE_ID", "instance-0")
+		c.Set("Logger.discardConsole", true)
+		c.Set("Logger.otlp.enabled", true)
+		c.Set("Logger.otlp.protocol", protocol)
+		c.Set("Logger.otlp.endpoint", endpoint)
+		c.Set("Logger.otlp.exportInterval", "1h") // records are flushed on Sync
+		c.Set("Logger.otlp.syncTimeout", "10s")
+		c.Set("Logger.otlp.retry.initialInterval", "1ms")
+		f := logger.NewFactory(c, options...)
+		t.Cleanup(func() { require.NoError(t, f.Close()) })
+		return c, f
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		receiver := &logsReceiver{}
+		_, f := newFactory(t, "grpc", receiver.serveGRPC(t), logger.WithServiceName("svc"), logger.WithServiceVersion("1.0.0"))
+		traceID, spanID := trace.TraceID{1, 2, 3}, trace.SpanID{4, 5, 6}
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+		log := f.NewLogger().Child("router").With("workspaceId", "ws")
+		log.WithContext(ctx).Infow("hello", "count", 1, "password", "p4ss")
+		f.NewLogger().Warnn("root", logger.NewBoolField("ok", true), logger.NewDurationField("took", 1500*time.Millisecond))
+		log.Debug("disabled level")
+		f.Sync()
+
+		requests := receiver.get()
+		require.Len(t, requests, 1)
+		require.Len(t, requests[0].ResourceLogs, 1)
+		rl := requests[0].ResourceLogs[0]
+		resource := attributes(rl.Resource.Attributes)
+		require.Equal(t, "svc", resource["service.name"])
+		require.Equal(t, "1.0.0", resource["service.version"])
+		require.Equal(t, "instance-0", resource["instanceName"])
+		require.Equal(t, "namespace", resource["namespace"])
+		require.NotEmpty(t, rl.SchemaUrl)
+
+		require.Len(t, rl.ScopeLogs, 2)
+		require.Equal(t, "router", rl.ScopeLogs[0].Scope.Name)
+		require.Len(t, rl.ScopeLogs[0].LogRecords, 1)
+		record := rl.ScopeLogs[0].LogRecords[0]
+		require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, record.SeverityNumber)
+		require.Equal(t, "INFO", record.SeverityText)
+		require.Equal(t, "hello", record.Body.GetStringValue())
+		require.NotZero(t, record.TimeUnixNano)
+		require.Equal(t, traceID[:], record.TraceId)
+		require.Equal(t, spanID[:], record.SpanId)
+		attrs := attributes(record.Attributes)
+		require.True(t, strings.HasSuffix(attrs["code.filepath"].(string), "otlp_test.go"), attrs["code.filepath"])
+		delete(attrs, "code.filepath")
+		delete(attrs, "code.lineno")
+		delete(attrs, "code.function")
+		require.Equal(t, map[string]any{"workspaceId": "ws", "count": int64(1), "password": "***"}, attrs, "fields are redacted")
+
+		require.Equal(t, "", rl.ScopeLogs[1].Scope.Name)
+		require.Len(t, rl.ScopeLogs[1].LogRecords, 1)
+		record = rl.ScopeLogs[1].LogRecords[0]
+		require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.SeverityNumber)
+		require.Equal(t, "root", record.Body.GetStringValue())
+		require.Empty(t, record.TraceId)
+		attrs = attributes(record.Attributes)
+		require.Equal(t, true, attrs["ok"])
+		require.Equal(t, "1.5s", attrs["took"])
+	})
+
+	t.Run("http", func(t *testing.T) {
+		receiver := &logsReceiver{failures: []error{status.Error(codes.Unavailable, "unavailable")}}
+		_, f := newFactory(t, "http", receiver.serveHTTP(t))
+		f.NewLogger().Info("hello")
+		f.Sync()
+		require.Equal(t, 2, receiver.getAttempts(), "retried")
+		require.Equal(t, []string{"hello"}, receiver.messages())
+	})
+
+	t.Run("retries and failures", func(t *testing.T) {
+		receiver := &logsReceiver{failures: []error{
+			status.Error(codes.Unavailable, "unavailable"),
+			status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			status.Error(codes.InvalidArgument, "invalid"),
+		}}
+		counters := &mockCounters{counts: make(map[string]int)}
+		_, f := newFactory(t, "grpc", receiver.serveGRPC(t), logger.WithCounters(counters.new))
+		log := f.NewLogger()
+		log.Info("dropped")
+		log.Info("dropped")
+		f.Sync()
+		require.Equal(t, 3, receiver.getAttempts(), "non retryable errors aren't retried")
+		require.Equal(t, 2, counters.get()["logger_otlp_dropped_entries,reason=export_failed"])
+
+		log.Info("exported")
+		f.Sync()
+		require.Equal(t, []string{"exported"}, receiver.messages())
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		receiver := &logsReceiver{block: make(chan struct{}), started: make(chan struct{}, 1)}
+		counters := &mockCounters{counts: make(map[string]int)}
+		c, _ := newFactory(t, "grpc", receiver.serveGRPC(t))
+		c.Set("Logger.otlp.queueSize", 2)
+		c.Set("Logger.otlp.batchSize", 1)
+		f := logger.NewFactory(c, logger.WithCounters(counters.new))
+		t.Cleanup(func() { require.NoError(t, f.Close()) })
+		log := f.NewLogger()
+		log.Info("first")
+		<-receiver.started // the exporter is now blocked
+		for i := 0; i < 5; i++ {
+			log.Info("next")
+		}
+		require.Equal(t, 3, counters.get()["logger_otlp_dropped_entries,reason=queue_full"], "logging doesn't block while the queue is full")
+
+		close(receiver.block)
+		f.Sync()
+		require.Equal(t, []string{"first", "next", "next"}, receiver.messages())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		receiver := &logsReceiver{}
+		c, _ := newFactory(t, "grpc", receiver.serveGRPC(t))
+		c.Set("Logger.otlp.enabled", false)
+		f := logger.NewFactory(c)
+		f.NewLogger().Info("hello")
+		require.NoError(t, f.Close())
+		require.Empty(t, receiver.get())
+	})
+
+	t.Run("sync doesn't wait for retries", func(t *testing.T) {
+		receiver := &logsReceiver{failures: []error{status.Error(codes.Unavailable, "unavailable")}}
+		counters := &mockCounters{counts: make(map[string]int)}
+		c, _ := newFactory(t, "grpc", receiver.serveGRPC(t))
+		c.Set("Logger.otlp.batchSize", 1) // exported right away
+		c.Set("Logger.otlp.syncTimeout", "1s")
+		c.Set("Logger.otlp.retry.initialInterval", "1h")
+		c.Set("Logger.otlp.retry.maxInterval", "1h")
+		c.Set("Logger.otlp.retry.maxElapsedTime", "2h")
+		f := logger.NewFactory(c, logger.WithCounters(counters.new))
+		f.NewLogger().Info("retried")
+		require.Eventually(t, func() bool {
+			start := time.Now()
+			f.Sync()
+			return receiver.getAttempts() == 1 && time.Since(start) < 100*time.Millisecond
+		}, 5*time.Second, time.Millisecond)
+
+		start := time.Now()
+		require.NoError(t, f.Close())
+		require.Less(t, time.Since(start), time.Second, "closing interrupts retries")
+		require.Equal(t, 1, counters.get()["logger_otlp_dropped_entries,reason=export_failed"])
+		start = time.Now()
+		f.Sync()
+		require.Less(t, time.Since(start), 100*time.Millisecond, "syncing a closed factory doesn't block")
+	})
+
+	t.Run("close exports the queued records", func(t *testing.T) {
+		receiver := &logsReceiver{}
+		c, _ := newFactory(t, "http", receiver.serveHTTP(t))
+		f := logger.NewFactory(c)
+		f.NewLogger().Info("hello")
+		require.NoError(t, f.Close())
+		require.Equal(t, []string{"hello"}, receiver.messages())
+	})
+}
+
+// logsReceiver is an in-process OTLP logs receiver
+type logsReceiver struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu       sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+	attempts int
+	failures []error // returned by the first exports
+
+	block   chan struct{} // blocking exports until closed, if not nil
+	started chan struct{} // notified when an export is blocked
+}
+
+func (r *logsReceiver) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	if r.block != nil {
+		select {
+		case r.started <- struct{}{}:
+		default:
+		}
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts++
+	if len(r.failures) > 0 {
+		err := r.failures[0]
+		r.failures = r.failures[1:]
+		return nil, err
+	}
+	r.requests = append(r.requests, req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (r *logsReceiver) get() []*collogspb.ExportLogsServiceRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+func (r *logsReceiver) getAttempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.attempts
+}
+
+// messages returns the messages of the records received so far, in order
+func (r *logsReceiver) messages() []string {
+	var messages []string
+	for _, req := range r.get() {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				for _, record := range sl.LogRecords {
+					messages = append(messages, record.Body.GetStringValue())
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// serveGRPC serves the receiver over gRPC, returning its endpoint
+func (r *logsReceiver) serveGRPC(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, r)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+// serveHTTP serves the receiver over HTTP, returning its endpoint
+func (r *logsReceiver) serveHTTP(t *testing.T) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, "/v1/logs", req.URL.Path)
+		require.Equal(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+		var exportReq collogspb.ExportLogsServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &exportReq))
+		res, err := r.Export(req.Context(), &exportReq)
+		if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resBody, err := proto.Marshal(res)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resBody)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]any {
+	res := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			res[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			res[kv.Key] = v.IntValue
+		case *commonpb.AnyValue_BoolValue:
+			res[kv.Key] = v.BoolValue
+		case *commonpb.AnyValue_DoubleValue:
+			res[kv.Key] = v.DoubleValue
+		default:
+			res[kv.Key] = kv.Value
+		}
+	}
+	return res
+}
@@ -0,0 +1,294 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+// redactedMask is what redacted values are replaced with, unless they are hashed
+const redactedMask = "***"
+
+// redactedHeaders are the request headers which LogRequest never logs in clear
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// secret is the value of the fields created with NewSecretField, which is masked however it gets formatted
+type secret string
+
+func (secret) String() string               { return redactedMask }
+func (secret) GoString() string             { return redactedMask }
+func (secret) MarshalText() ([]byte, error) { return []byte(redactedMask), nil }
+
+// redactionConfig is the hot-reloadable configuration of the redaction of log entries
+type redactionConfig struct {
+	enabled config.ValueLoader[bool]
+	// names of the fields whose values are redacted, matched case-insensitively against the last words of the field
+	// names, split on '_', '-', '.' and camelCase, e.g. "token" matches "accessToken", "api_token" and "X-Auth-Token" but
+	// neither "tokenCount" nor "totalTokens". Only the top-level fields are matched: the keys nested in maps, structs
+	// and objects, e.g. logged with zap.Any, aren't inspected, though their whole value is redacted if the field matches
+	fields config.ValueLoader[[]string]
+	// regular expressions matching the parts of messages, string values and error messages which are redacted. Other
+	// values, e.g. maps, structs, objects and stringers, aren't inspected
+	patterns config.ValueLoader[[]string]
+	// whether redacted values are replaced by a hash, allowing to correlate them, rather than masked
+	hash config.ValueLoader[bool]
+	// the key of the HMAC used for hashing. Values are masked rather than hashed if it is empty, since plain hashes of
+	// low-entropy values such as passwords can be reversed with a dictionary
+	hashKey config.ValueLoader[string]
+}
+
+func newRedactionConfig(c *config.Config) redactionConfig {
+	return redactionConfig{
+		enabled:  c.GetReloadableBoolVar(true, "Logger.redaction.enabled"),
+		fields:   c.GetReloadableStringSliceVar([]string{"password", "secret", "token", "authorization"}, "Logger.redaction.fields"),
+		patterns: c.GetReloadableStringSliceVar(nil, "Logger.redaction.patterns"),
+		hash:     c.GetReloadableBoolVar(false, "Logger.redaction.hash"),
+		hashKey:  c.GetReloadableStringVar("", "Logger.redaction.hashKey"),
+	}
+}
+
+// redactor redacts the values of sensitive fields along with the parts of string values and messages matching the
+// configured patterns
+type redactor struct {
+	config   redactionConfig
+	names    atomic.Pointer[loadedNames]
+	patterns atomic.Pointer[loadedPatterns]
+}
+
+// loadedNames are the field names last split in words from the configuration, see nameWords
+type loadedNames struct {
+	config []string
+	words  [][]string
+}
+
+// loadedPatterns are the patterns last compiled from the configuration, invalid ones being ignored
+type loadedPatterns struct {
+	config []string
+	regexp *regexp.Regexp // all the patterns combined, nil if there are none
+}
+
+func newRedactor(config redactionConfig) *redactor {
+	return &redactor{config: config}
+}
+
+// redactFields returns the fields with their sensitive values redacted, reusing the given slice only if no field
+// needs to be redacted
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	enabled := r.config.enabled.Load()
+	var (
+		names    [][]string
+		patterns *regexp.Regexp
+	)
+	if enabled {
+		names, patterns = r.loadNames(), r.loadPatterns()
+	}
+	var res []zapcore.Field
+	for i, f := range fields {
+		redacted, ok := r.redactField(f, enabled, names, patterns)
+		if !ok {
+			if res != nil {
+				res = append(res, f)
+			}
+			continue
+		}
+		if res == nil {
+			res = make([]zapcore.Field, i, len(fields))
+			copy(res, fields[:i])
+		}
+		res = append(res, redacted)
+	}
+	if res == nil {
+		return fields
+	}
+	return res
+}
+
+// redactField returns the redacted field and true if the field needs to be redacted
+func (r *redactor) redactField(f zapcore.Field, enabled bool, names [][]string, patterns *regexp.Regexp) (zapcore.Field, bool) {
+	if s, ok := f.Interface.(secret); ok && f.Type == zapcore.StringerType {
+		// secrets are always redacted, even if redaction is disabled
+		return zap.String(f.Key, r.redactValue(string(s))), true
+	}
+	if !enabled {
+		return f, false
+	}
+	if len(names) > 0 {
+		var buf [8]string
+		key := nameWords(buf[:0], f.Key)
+		for _, name := range names {
+			if !endsWithWords(key, name) {
+				continue
+			}
+			if f.Type == zapcore.StringType {
+				return zap.String(f.Key, r.redactValue(f.String)), true
+			}
+			return zap.String(f.Key, redactedMask), true
+		}
+	}
+	if patterns == nil {
+		return f, false
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		if redacted := r.redactString(f.String, patterns); redacted != f.String {
+			return zap.String(f.Key, redacted), true
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			if msg := err.Error(); patterns.MatchString(msg) {
+				return zap.String(f.Key, r.redactString(msg, patterns)), true
+			}
+		}
+	}
+	return f, false
+}
+
+// nameWords appends the words of a field name to words, splitting it on '_', '-', '.' and
+// camelCase boundaries, e.g. "X-Auth-Token", "x_auth.token" and "xAuthToken" are all made of "x", "auth" and "token"
+func nameWords(words []string, name string) []string {
+	isUpper := func(c byte) bool { return 'A' <= c && c <= 'Z' }
+	isLower := func(c byte) bool { return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' }
+	start := 0
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c == '_' || c == '-' || c == '.':
+			if i > start {
+				words = append(words, name[start:i])
+			}
+			start = i + 1
+		case isUpper(c) && i > start && (isLower(name[i-1]) || isUpper(name[i-1]) && i+1 < len(name) && isLower(name[i+1])):
+			// e.g. "apiToken" and "APIToken" are both made of "api" and "token"
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	if start < len(name) {
+		words = append(words, name[start:])
+	}
+	return words
+}
+
+// endsWithWords returns whether the words of a field name end with the given ones, ignoring case
+func endsWithWords(words, suffix []string) bool {
+	if len(suffix) == 0 || len(suffix) > len(words) {
+		return false
+	}
+	words = words[len(words)-len(suffix):]
+	for i := range suffix {
+		if !strings.EqualFold(words[i], suffix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// redactMessage returns the message with the parts matching the configured patterns redacted
+func (r *redactor) redactMessage(msg string) string {
+	if !r.config.enabled.Load() {
+		return msg
+	}
+	if patterns := r.loadPatterns(); patterns != nil {
+		return r.redactString(msg, patterns)
+	}
+	return msg
+}
+
+func (r *redactor) redactString(s string, patterns *regexp.Regexp) string {
+	return patterns.ReplaceAllStringFunc(s, r.redactValue)
+}
+
+// redactValue returns the mask, or the keyed hash of the value if hashing is enabled and a key is configured
+func (r *redactor) redactValue(v string) string {
+	if !r.config.hash.Load() {
+		return redactedMask
+	}
+	key := r.config.hashKey.Load()
+	if key == "" {
+		return redactedMask
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(v))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func (r *redactor) loadNames() [][]string {
+	namesConfig := r.config.fields.Load()
+	if loaded := r.names.Load(); loaded != nil && slices.Equal(loaded.config, namesConfig) {
+		return loaded.words
+	}
+	loaded := &loadedNames{config: namesConfig}
+	for _, name := range namesConfig {
+		if words := nameWords(nil, name); len(words) > 0 {
+			loaded.words = append(loaded.words, words)
+		}
+	}
+	r.names.Store(loaded)
+	return loaded.words
+}
+
+func (r *redactor) loadPatterns() *regexp.Regexp {
+	patternsConfig := r.config.patterns.Load()
+	if loaded := r.patterns.Load(); loaded != nil && slices.Equal(loaded.config, patternsConfig) {
+		return loaded.regexp
+	}
+	loaded := &loadedPatterns{config: patternsConfig}
+	var valid []string
+	for _, pattern := range patternsConfig {
+		if _, err := regexp.Compile(pattern); err == nil && pattern != "" {
+			valid = append(valid, "(?:"+pattern+")")
+		}
+	}
+	if len(valid) > 0 {
+		loaded.regexp = regexp.MustCompile(strings.Join(valid, "|"))
+	}
+	r.patterns.Store(loaded)
+	return loaded.regexp
+}
+
+// redactHeaders returns a copy of the headers with the values of the sensitive ones redacted through the given function
+func redactHeaders(headers http.Header, redact func(string) string) http.Header {
+	res := headers.Clone()
+	for _, name := range redactedHeaders {
+		values := res.Values(name)
+		for i := range values {
+			values[i] = redact(values[i])
+		}
+	}
+	return res
+}
+
+// redactingCore is a zapcore.Core redacting the fields and messages of the entries it logs, see redactor
+type redactingCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func newRedactingCore(core zapcore.Core, redactor *redactor) zapcore.Core {
+	return &redactingCore{Core: core, redactor: redactor}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactor.redactFields(fields)), redactor: c.redactor}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.redactMessage(entry.Message)
+	return c.Core.Write(entry, c.redactor.redactFields(fields))
+}
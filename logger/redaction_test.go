@@ -0,0 +1,111 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestRedaction(t *testing.T) {
+	newLoggerWithConfig := func(t *testing.T, c *config.Config) (*config.Config, logger.Logger, func() []string) {
+		fileName := t.TempDir() + "out.log"
+		c.Set("LOG_LEVEL", "EVENT")
+		c.Set("Logger.enableTimestamp", false)
+		c.Set("Logger.enableConsole", false)
+		c.Set("Logger.enableFile", true)
+		c.Set("Logger.enableFileNameInLog", false)
+		c.Set("Logger.enableLoggerNameInLog", false)
+		c.Set("Logger.logFileLocation", fileName)
+		f := logger.NewFactory(c)
+		t.Cleanup(func() { _ = f.Close() })
+		return c, f.NewLogger(), func() []string {
+			out, err := os.ReadFile(fileName)
+			require.NoError(t, err)
+			return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		}
+	}
+	newLogger := func(t *testing.T) (*config.Config, logger.Logger, func() []string) {
+		return newLoggerWithConfig(t, config.New())
+	}
+
+	t.Run("field names", func(t *testing.T) {
+		_, log, lines := newLogger(t)
+		log.With("token", "abc").Infow("hello", "Password", "p4ss", "user", "john", "SECRET", 3)
+		log.Withn(logger.NewStringField("authorization", "Bearer abc")).Infon("hello", logger.NewIntField("n", 1))
+		log.Infow("hello", "accessToken", "abc", "api_token", "abc", "db_password", "p4ss", "clientSecret", "abc", "X-Auth-Token", "abc")
+		log.Infow("hello", "totalTokens", 10, "tokenCount", 2, "secretName", "db", "passwords_count", 1)
+		log.Infow("hello", "password", map[string]string{"user": "john", "password": "p4ss"}, "credentials", map[string]string{"password": "p4ss"})
+		require.Equal(t, []string{
+			`INFO	hello	{"token": "***", "Password": "***", "user": "john", "SECRET": "***"}`,
+			`INFO	hello	{"authorization": "***", "n": 1}`,
+			`INFO	hello	{"accessToken": "***", "api_token": "***", "db_password": "***", "clientSecret": "***", "X-Auth-Token": "***"}`,
+			`INFO	hello	{"totalTokens": 10, "tokenCount": 2, "secretName": "db", "passwords_count": 1}`,
+			`INFO	hello	{"password": "***", "credentials": {"password":"p4ss"}}`,
+		}, lines(), "field names are matched against the last words of the field names, ignoring case, nested keys aren't inspected")
+
+		c := config.New()
+		c.Set("Logger.redaction.fields", []string{"api_key"})
+		_, log, lines = newLoggerWithConfig(t, c)
+		log.Infow("hello", "stripeApiKey", "abc", "APIKey", "abc", "api-key", "abc", "key", "abc", "apiKeyID", 1)
+		require.Equal(t, []string{
+			`INFO	hello	{"stripeApiKey": "***", "APIKey": "***", "api-key": "***", "key": "abc", "apiKeyID": 1}`,
+		}, lines(), "names made of several words")
+	})
+
+	t.Run("patterns", func(t *testing.T) {
+		c, log, lines := newLogger(t)
+		c.Set("Logger.redaction.patterns", []string{`sk_live_[a-z0-9]+`, `[invalid`, `\d{4}-\d{4}-\d{4}-\d{4}`})
+		log.Infow("charging card 1234-5678-9012-3456", "key", "using sk_live_abc123 key", "error", errors.New("sk_live_abc123"))
+		require.Equal(t, []string{
+			`INFO	charging card ***	{"key": "using *** key", "error": "***"}`,
+		}, lines(), "patterns apply to messages, strings and errors")
+	})
+
+	t.Run("secret fields", func(t *testing.T) {
+		c, log, lines := newLogger(t)
+		c.Set("Logger.redaction.enabled", false)
+		log.Infon("hello", logger.NewSecretField("apiKey", "abc"), logger.NewStringField("password", "p4ss"))
+		log.Infow("hello", logger.Expand(logger.NewSecretField("apiKey", "abc"))...)
+		require.Equal(t, []string{
+			`INFO	hello	{"apiKey": "***", "password": "p4ss"}`,
+			`INFO	hello	{"apiKey": "***"}`,
+		}, lines(), "secrets are redacted even if redaction is disabled")
+		value := logger.NewSecretField("apiKey", "abc").Value()
+		require.Equal(t, "*** *** ***", fmt.Sprintf("%v %s %#v", value, value, value))
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		c, log, lines := newLogger(t)
+		c.Set("Logger.redaction.hash", true)
+		log.Infon("hello", logger.NewSecretField("apiKey", "abc"), logger.NewStringField("token", "abc"))
+		c.Set("Logger.redaction.hashKey", "key")
+		log.Infon("hello", logger.NewSecretField("apiKey", "abc"), logger.NewStringField("token", "abc"))
+		require.Equal(t, []string{
+			`INFO	hello	{"apiKey": "***", "token": "***"}`,
+			`INFO	hello	{"apiKey": "sha256:9c196e32dc0175f8", "token": "sha256:9c196e32dc0175f8"}`,
+		}, lines(), "values are hashed only if a key is configured")
+	})
+
+	t.Run("request headers", func(t *testing.T) {
+		_, log, lines := newLogger(t)
+		request, err := http.NewRequest(http.MethodPost, "https://example.com", bytes.NewReader([]byte("{}")))
+		require.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer abc")
+		request.Header.Set("Cookie", "session=abc")
+		request.Header.Set("Content-Type", "application/json")
+		log.LogRequest(request)
+		require.Equal(t, []string{
+			`DEBUG	Request Body	{"body": "{}", "headers": {"Authorization":["***"],"Content-Type":["application/json"],"Cookie":["***"]}}`,
+		}, lines())
+		require.Equal(t, "Bearer abc", request.Header.Get("Authorization"), "request headers are left untouched")
+	})
+}
@@ -0,0 +1,360 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+const (
+	// backupTimeFormat is the format of the timestamps lumberjack suffixes backup files with
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	megabyte         = 1024 * 1024
+)
+
+// RotatedFileHandler handles a log file once rotated and compressed, e.g. uploading it to a remote storage.
+// The file is deleted locally if the handler succeeds, see WithRotatedFileHandler.
+type RotatedFileHandler func(ctx context.Context, path string) error
+
+// rotationConfig is the configuration of the rotation of the log file
+type rotationConfig struct {
+	filename    string
+	maxSize     int           // the size in megabytes the file is rotated at
+	maxAge      time.Duration // the age rotated files are deleted at, 0 for keeping them regardless of their age
+	maxBackups  int           // the number of rotated files which are kept, 0 for keeping all of them
+	interval    time.Duration // the interval the file is rotated at regardless of its size, i.e. daily or hourly, 0 if none
+	compression string        // the format rotated files are compressed in: gzip, zstd or none
+	handler     RotatedFileHandler
+}
+
+// mills returns whether rotated files have to be milled, i.e. compressed, handled or deleted
+func (rc rotationConfig) mills() bool {
+	return rc.compression != "none" || rc.maxAge > 0 || rc.maxBackups > 0 || rc.handler != nil
+}
+
+func newRotationConfig(c *config.Config, handler RotatedFileHandler) rotationConfig {
+	rc := rotationConfig{
+		filename:    c.GetString("Logger.logFileLocation", "/tmp/rudder_log.log"),
+		maxSize:     max(c.GetInt("Logger.logFileSize", 100), 1),
+		maxAge:      time.Duration(c.GetInt("Logger.logFileMaxAge", 0)) * 24 * time.Hour,
+		maxBackups:  c.GetInt("Logger.logFileMaxBackups", 0),
+		compression: strings.ToLower(c.GetString("Logger.logFileCompression", "gzip")),
+		handler:     handler,
+	}
+	switch strings.ToLower(c.GetString("Logger.logFileRotation", "")) {
+	case "daily":
+		rc.interval = 24 * time.Hour
+	case "hourly":
+		rc.interval = time.Hour
+	}
+	switch rc.compression {
+	case "gzip", "zstd", "none":
+	default:
+		rc.compression = "gzip"
+	}
+	return rc
+}
+
+// rotatingWriter writes to a log file through lumberjack, rotating the file when it reaches its maximum size or at the
+// configured interval. Rotated files are then compressed, passed to the rotated file handler, if any, and deleted
+// according to the retention settings, in the background, until the writer is closed.
+type rotatingWriter struct {
+	config rotationConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	file    *lumberjack.Logger
+	size    int64       // the size of the file, for telling when lumberjack rotates it
+	started bool        // whether the background work is enabled, see start
+	milling bool        // whether the mill goroutine is running
+	timer   *time.Timer // rotating the file at the configured interval, if any
+
+	mill   chan struct{}
+	ctx    context.Context // cancelled by Close, stopping the background work
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newRotatingWriter(config rotationConfig, now func() time.Time) *rotatingWriter {
+	w := &rotatingWriter{
+		config: config,
+		now:    now,
+		file: &lumberjack.Logger{
+			Filename:  config.filename,
+			MaxSize:   config.maxSize,
+			LocalTime: true,
+			// compression and retention are handled by the mill, lumberjack not allowing to hook into them
+		},
+		mill: make(chan struct{}, 1),
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	if info, err := os.Stat(config.filename); err == nil {
+		w.size = info.Size()
+	}
+	return w
+}
+
+// start starts rotating the file at the configured interval, if any, and milling rotated files from the first rotation
+// on, including the ones left over by a previous process. The mill goroutine is only started by the first rotation, and
+// only if there is something to mill, see rotationConfig.mills.
+func (w *rotatingWriter) start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.started = true
+	if w.config.interval > 0 {
+		w.timer = time.AfterFunc(nextRotation(w.now(), w.config.interval).Sub(w.now()), w.rotateOnInterval)
+	}
+}
+
+func (w *rotatingWriter) rotateOnInterval() {
+	if err := w.Rotate(); err != nil {
+		reportError(fmt.Errorf("rotating log file: %w", err))
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ctx.Err() == nil {
+		w.timer.Reset(nextRotation(w.now(), w.config.interval).Sub(w.now()))
+	}
+}
+
+// Close stops rotating and milling files, waiting for the file being milled, if any, and closes the file. Rotated files
+// which are left over are milled by the next process.
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	w.cancel()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	w.wg.Wait()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rotated := w.size+int64(len(p)) > int64(w.config.maxSize)*megabyte
+	n, err := w.file.Write(p)
+	if rotated {
+		w.size = int64(n)
+		w.triggerMill()
+	} else {
+		w.size += int64(n)
+	}
+	return n, err
+}
+
+func (w *rotatingWriter) Sync() error {
+	return nil
+}
+
+// Rotate rotates the file, unless it is empty or the writer is closed
+func (w *rotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size == 0 || w.ctx.Err() != nil {
+		return nil
+	}
+	if err := w.file.Rotate(); err != nil {
+		return err
+	}
+	w.size = 0
+	w.triggerMill()
+	return nil
+}
+
+// triggerMill triggers milling the rotated files, starting the mill goroutine if needed. It is called with the lock held.
+func (w *rotatingWriter) triggerMill() {
+	if !w.config.mills() {
+		return
+	}
+	if w.started && !w.milling && w.ctx.Err() == nil {
+		w.milling = true
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			for {
+				select {
+				case <-w.ctx.Done():
+					return
+				case <-w.mill:
+					w.millRotated()
+				}
+			}
+		}()
+	}
+	select {
+	case w.mill <- struct{}{}:
+	default: // already triggered
+	}
+}
+
+// nextRotation returns the time of the next rotation after t, i.e. the next local midnight for daily rotations or
+// the next hour otherwise
+func nextRotation(t time.Time, interval time.Duration) time.Time {
+	if interval == 24*time.Hour {
+		y, m, d := t.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+	}
+	return t.Truncate(interval).Add(interval)
+}
+
+// rotatedFile is a file rotated by lumberjack
+type rotatedFile struct {
+	path       string
+	time       time.Time
+	compressed bool
+}
+
+// millRotated compresses the rotated files which aren't yet, passes them to the handler, if any, deleting the ones
+// handled successfully, and finally deletes the files exceeding the retention settings
+func (w *rotatingWriter) millRotated() {
+	files, err := w.rotatedFiles()
+	if errors.Is(err, fs.ErrNotExist) {
+		return // nothing logged yet
+	}
+	if err != nil {
+		reportError(fmt.Errorf("listing rotated log files: %w", err))
+		return
+	}
+	for i := range files {
+		if files[i].compressed || w.config.compression == "none" {
+			continue
+		}
+		compressed, err := compressFile(files[i].path, w.config.compression)
+		if err != nil {
+			reportError(fmt.Errorf("compressing rotated log file: %w", err))
+			continue
+		}
+		files[i].path, files[i].compressed = compressed, true
+	}
+
+	if w.config.handler != nil {
+		remaining := files[:0]
+		for _, f := range files {
+			if w.ctx.Err() != nil {
+				return // closed, the remaining files being handled by the next process
+			}
+			if err := w.config.handler(w.ctx, f.path); err != nil {
+				reportError(fmt.Errorf("handling rotated log file %q: %w", f.path, err))
+				remaining = append(remaining, f)
+				continue
+			}
+			if err := os.Remove(f.path); err != nil {
+				reportError(fmt.Errorf("removing handled log file: %w", err))
+			}
+		}
+		files = remaining
+	}
+
+	cutoff := w.now().Add(-w.config.maxAge)
+	for i, f := range files {
+		if (w.config.maxBackups > 0 && i >= w.config.maxBackups) || (w.config.maxAge > 0 && f.time.Before(cutoff)) {
+			if err := os.Remove(f.path); err != nil {
+				reportError(fmt.Errorf("removing expired log file: %w", err))
+			}
+		}
+	}
+}
+
+// rotatedFiles returns the files rotated by lumberjack, newest first
+func (w *rotatingWriter) rotatedFiles() ([]rotatedFile, error) {
+	dir := filepath.Dir(w.config.filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Base(w.config.filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	var files []rotatedFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		f := rotatedFile{path: filepath.Join(dir, name)}
+		ts := strings.TrimPrefix(name, prefix)
+		for _, suffix := range []string{".gz", ".zst"} {
+			if strings.HasSuffix(ts, ext+suffix) {
+				ts, f.compressed = strings.TrimSuffix(ts, suffix), true
+				break
+			}
+		}
+		if !strings.HasSuffix(ts, ext) {
+			continue
+		}
+		if f.time, err = time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(ts, ext), time.Local); err != nil {
+			continue
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].time.After(files[j].time) })
+	return files, nil
+}
+
+// compressFile compresses the file in the given format, removing the original one, and returns the path of the
+// compressed file
+func compressFile(path, format string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = src.Close() }()
+
+	res := path + ".gz"
+	if format == "zstd" {
+		res = path + ".zst"
+	}
+	dst, err := os.OpenFile(res, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if err := compressTo(dst, src, format); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(res)
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(res)
+		return "", err
+	}
+	return res, os.Remove(path)
+}
+
+func compressTo(dst io.Writer, src io.Reader, format string) error {
+	var w io.WriteCloser = gzip.NewWriter(dst)
+	if format == "zstd" {
+		var err error
+		if w, err = zstd.NewWriter(dst); err != nil {
+			return err
+		}
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// reportError reports errors occurring in the background, like zap does for its internal errors
+func reportError(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "%v logger error: %v\n", time.Now(), err)
+}
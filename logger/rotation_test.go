@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	now := time.Date(2077, 1, 23, 10, 15, 13, 0, time.Local)
+	listFiles := func(t *testing.T, dir string) []string {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		sort.Strings(names)
+		return names
+	}
+	backup := func(t *testing.T, dir string, ts time.Time, suffix string) {
+		name := filepath.Join(dir, "app-"+ts.Format(backupTimeFormat)+".log"+suffix)
+		require.NoError(t, os.WriteFile(name, []byte("backup"), 0o644))
+	}
+
+	t.Run("config", func(t *testing.T) {
+		c := config.New()
+		c.Set("Logger.logFileMaxAge", 7)
+		c.Set("Logger.logFileMaxBackups", 3)
+		c.Set("Logger.logFileRotation", "Hourly")
+		c.Set("Logger.logFileCompression", "zstd")
+		rc := newRotationConfig(c, nil)
+		require.Equal(t, 7*24*time.Hour, rc.maxAge)
+		require.Equal(t, 3, rc.maxBackups)
+		require.Equal(t, time.Hour, rc.interval)
+		require.Equal(t, "zstd", rc.compression)
+		require.Equal(t, 100, rc.maxSize)
+
+		c.Set("Logger.logFileCompression", "lz4")
+		require.Equal(t, "gzip", newRotationConfig(c, nil).compression, "unknown formats default to gzip")
+	})
+
+	t.Run("size and time based rotation with gzip", func(t *testing.T) {
+		dir := t.TempDir()
+		w := newRotatingWriter(rotationConfig{filename: filepath.Join(dir, "app.log"), maxSize: 1, compression: "gzip"}, func() time.Time { return now })
+		_, err := w.Write(bytes.Repeat([]byte("a"), megabyte))
+		require.NoError(t, err)
+		require.Empty(t, w.mill, "not rotated yet")
+		_, err = w.Write([]byte("b"))
+		require.NoError(t, err)
+		require.Len(t, w.mill, 1, "rotated by lumberjack")
+		<-w.mill
+		time.Sleep(time.Millisecond) // lumberjack names backups after the current time, in milliseconds
+		_, err = w.Write([]byte("c"))
+		require.NoError(t, err)
+		require.NoError(t, w.Rotate())
+		require.NoError(t, w.Rotate(), "empty files aren't rotated")
+		w.millRotated()
+
+		files := listFiles(t, dir)
+		require.Len(t, files, 3)
+		require.Equal(t, "app.log", files[2])
+		var contents []string
+		for _, name := range files[:2] {
+			require.True(t, strings.HasSuffix(name, ".log.gz"), name)
+			f, err := os.Open(filepath.Join(dir, name))
+			require.NoError(t, err)
+			r, err := gzip.NewReader(f)
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			contents = append(contents, string(content))
+		}
+		require.Equal(t, []string{strings.Repeat("a", megabyte), "bc"}, contents)
+	})
+
+	t.Run("zstd", func(t *testing.T) {
+		dir := t.TempDir()
+		backup(t, dir, now, "")
+		w := newRotatingWriter(rotationConfig{filename: filepath.Join(dir, "app.log"), maxSize: 1, compression: "zstd"}, func() time.Time { return now })
+		w.millRotated()
+		files := listFiles(t, dir)
+		require.Equal(t, []string{"app-" + now.Format(backupTimeFormat) + ".log.zst"}, files)
+		f, err := os.Open(filepath.Join(dir, files[0]))
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		r, err := zstd.NewReader(f)
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "backup", string(content))
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		dir := t.TempDir()
+		for i := 0; i < 5; i++ {
+			backup(t, dir, now.Add(-time.Duration(i)*time.Hour), ".gz")
+		}
+		backup(t, dir, now.Add(-10*24*time.Hour), ".gz")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "app-other.log"), nil, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "other.log"), nil, 0o644))
+
+		w := newRotatingWriter(rotationConfig{filename: filepath.Join(dir, "app.log"), maxSize: 1, compression: "none", maxBackups: 6, maxAge: 7 * 24 * time.Hour}, func() time.Time { return now })
+		w.millRotated()
+		require.Len(t, listFiles(t, dir), 7, "the backup older than 7 days is removed")
+
+		w.config.maxBackups = 2
+		w.millRotated()
+		require.Equal(t, []string{
+			"app-" + now.Add(-time.Hour).Format(backupTimeFormat) + ".log.gz",
+			"app-" + now.Format(backupTimeFormat) + ".log.gz",
+			"app-other.log",
+			"other.log",
+		}, listFiles(t, dir))
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		dir := t.TempDir()
+		backup(t, dir, now, "")
+		backup(t, dir, now.Add(-time.Hour), "")
+		var handled []string
+		fail := true
+		w := newRotatingWriter(rotationConfig{
+			filename: filepath.Join(dir, "app.log"), maxSize: 1, compression: "gzip",
+			handler: func(_ context.Context, path string) error {
+				_, err := os.Stat(path)
+				require.NoError(t, err)
+				handled = append(handled, filepath.Base(path))
+				if fail && len(handled) == 1 {
+					return errors.New("upload failed")
+				}
+				return nil
+			},
+		}, func() time.Time { return now })
+		w.millRotated()
+		newest := "app-" + now.Format(backupTimeFormat) + ".log.gz"
+		oldest := "app-" + now.Add(-time.Hour).Format(backupTimeFormat) + ".log.gz"
+		require.Equal(t, []string{newest, oldest}, handled)
+		require.Equal(t, []string{newest}, listFiles(t, dir), "files are deleted once handled")
+
+		fail = false
+		w.millRotated()
+		require.Equal(t, []string{newest, oldest, newest}, handled, "failures are retried")
+		require.Empty(t, listFiles(t, dir))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		dir := t.TempDir()
+		backup(t, dir, now, "")
+		backup(t, dir, now.Add(-time.Hour), "")
+		handling := make(chan struct{})
+		var handled []error
+		w := newRotatingWriter(rotationConfig{
+			filename: filepath.Join(dir, "app.log"), maxSize: 1, compression: "none", interval: time.Hour,
+			handler: func(ctx context.Context, _ string) error {
+				close(handling)
+				<-ctx.Done() // e.g. an upload, interrupted by Close
+				handled = append(handled, ctx.Err())
+				return ctx.Err()
+			},
+		}, time.Now)
+		w.start()
+		_, err := w.Write([]byte("a"))
+		require.NoError(t, err)
+		require.False(t, w.milling, "the mill is started by the first rotation")
+		require.NoError(t, w.Rotate())
+		<-handling
+
+		require.NoError(t, w.Close(), "returns once the background work stopped")
+		require.Equal(t, []error{context.Canceled}, handled, "the remaining files are left to the next process")
+		require.Len(t, listFiles(t, dir), 4)
+		w.triggerMill()
+		require.Len(t, w.mill, 1, "files aren't milled anymore")
+		require.NoError(t, w.Close(), "closing is idempotent")
+	})
+
+	t.Run("nothing to mill", func(t *testing.T) {
+		w := newRotatingWriter(rotationConfig{filename: filepath.Join(t.TempDir(), "app.log"), maxSize: 1, compression: "none"}, time.Now)
+		w.start()
+		_, err := w.Write([]byte("a"))
+		require.NoError(t, err)
+		require.NoError(t, w.Rotate())
+		require.False(t, w.milling, "no mill is started without compression, retention nor handler")
+		require.NoError(t, w.Close())
+	})
+
+	t.Run("next rotation", func(t *testing.T) {
+		require.Equal(t, time.Date(2077, 1, 24, 0, 0, 0, 0, time.Local), nextRotation(now, 24*time.Hour))
+		require.Equal(t, time.Date(2077, 1, 23, 11, 0, 0, 0, time.Local), nextRotation(now, time.Hour))
+	})
+}
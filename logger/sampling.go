@@ -0,0 +1,280 @@
+package logger
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+const (
+	// samplingCountersPerLevel is the number of counters entries are hashed into, per level
+	samplingCountersPerLevel = 4096
+	// suppressedEntriesMetric is the name of the counter of the entries suppressed by sampling
+	suppressedEntriesMetric = "logger_suppressed_entries"
+)
+
+// Counter counts events, e.g. a stats.Measurement of type stats.CountType
+type Counter interface {
+	Count(n int)
+}
+
+// samplingConfig is the hot-reloadable configuration of the sampling of log entries:
+// during each interval the first entries with the same module, level and message are logged,
+// then only one every thereafter entries. Errors, including fatal entries, are never sampled.
+type samplingConfig struct {
+	enabled        config.ValueLoader[bool]
+	interval       config.ValueLoader[time.Duration]
+	first          config.ValueLoader[int]
+	thereafter     config.ValueLoader[int]
+	reportInterval config.ValueLoader[time.Duration]
+	// colon separated module=first/thereafter pairs, "off" disabling sampling for the module
+	// Example: "router.GA=10/1000:warehouse=off"
+	modules config.ValueLoader[string]
+}
+
+func newSamplingConfig(c *config.Config) samplingConfig {
+	return samplingConfig{
+		enabled:        c.GetReloadableBoolVar(false, "Logger.sampling.enabled"),
+		interval:       c.GetReloadableDurationVar(1, time.Second, "Logger.sampling.interval"),
+		first:          c.GetReloadableIntVar(100, 1, "Logger.sampling.first"),
+		thereafter:     c.GetReloadableIntVar(100, 1, "Logger.sampling.thereafter"),
+		reportInterval: c.GetReloadableDurationVar(1, time.Minute, "Logger.sampling.reportInterval"),
+		modules:        c.GetReloadableStringVar("", "Logger.sampling.modules"),
+	}
+}
+
+// moduleSampling is the sampling configured for a module
+type moduleSampling struct {
+	disabled          bool
+	first, thereafter uint64
+}
+
+// parseModuleSampling parses colon separated module=first/thereafter pairs, ignoring invalid ones
+func parseModuleSampling(s string) map[string]moduleSampling {
+	modules := make(map[string]moduleSampling)
+	for _, kv := range strings.Split(strings.TrimSpace(s), ":") {
+		module, value, ok := strings.Cut(kv, "=")
+		module, value = strings.TrimSpace(module), strings.TrimSpace(value)
+		if !ok || module == "" {
+			continue
+		}
+		if strings.EqualFold(value, "off") {
+			modules[module] = moduleSampling{disabled: true}
+			continue
+		}
+		firstStr, thereafterStr, ok := strings.Cut(value, "/")
+		if !ok {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSpace(firstStr), 10, 64)
+		if err != nil {
+			continue
+		}
+		thereafter, err := strconv.ParseUint(strings.TrimSpace(thereafterStr), 10, 64)
+		if err != nil {
+			continue
+		}
+		modules[module] = moduleSampling{first: first, thereafter: thereafter}
+	}
+	return modules
+}
+
+// sampler decides which entries are logged, counting the suppressed ones by module and level.
+// Counts are reported through counters, if configured (see WithCounters), and logged at most once per report
+// interval, when entries are checked, for the modules logging warnings.
+type sampler struct {
+	config     samplingConfig
+	newCounter func(name string, tags map[string]string) Counter
+	clock      zapcore.Clock
+	level      func(module string) int // the log level of a module, see factoryConfig.moduleLevel
+
+	counts [zapcore.WarnLevel - zapcore.DebugLevel + 1][samplingCountersPerLevel]samplingCounter
+
+	modules atomic.Pointer[loadedModuleSampling]
+
+	suppressed sync.Map // suppressedKey -> *suppressedCount
+	lastReport atomic.Int64
+}
+
+type suppressedKey struct {
+	module string
+	level  zapcore.Level
+}
+
+type suppressedCount struct {
+	count    atomic.Int64
+	reported int64 // protected by the lastReport CAS
+	counter  Counter
+}
+
+func newSampler(config samplingConfig, newCounter func(name string, tags map[string]string) Counter, clock zapcore.Clock, level func(module string) int) *sampler {
+	s := &sampler{config: config, newCounter: newCounter, clock: clock, level: level}
+	s.lastReport.Store(clock.Now().UnixNano())
+	return s
+}
+
+// sample returns true if the entry should be logged, errors always being logged
+func (s *sampler) sample(entry zapcore.Entry) bool {
+	if entry.Level < zapcore.DebugLevel || entry.Level >= zapcore.ErrorLevel {
+		return true
+	}
+	ms := s.moduleSampling(entry.LoggerName)
+	if ms.disabled {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(entry.LoggerName))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(entry.Message))
+	counter := &s.counts[entry.Level-zapcore.DebugLevel][h.Sum32()%samplingCountersPerLevel]
+	n := counter.incCheckReset(entry.Time, s.config.interval.Load())
+	if n <= ms.first || (ms.thereafter > 0 && (n-ms.first)%ms.thereafter == 0) {
+		return true
+	}
+	s.suppress(entry)
+	return false
+}
+
+func (s *sampler) suppress(entry zapcore.Entry) {
+	key := suppressedKey{module: entry.LoggerName, level: entry.Level}
+	res, ok := s.suppressed.Load(key)
+	if !ok {
+		sc := &suppressedCount{}
+		if s.newCounter != nil {
+			sc.counter = s.newCounter(suppressedEntriesMetric, map[string]string{
+				"module": entry.LoggerName, "level": entry.Level.CapitalString(),
+			})
+		}
+		res, _ = s.suppressed.LoadOrStore(key, sc)
+	}
+	sc := res.(*suppressedCount)
+	sc.count.Add(1)
+	if sc.counter != nil {
+		sc.counter.Count(1)
+	}
+}
+
+// report logs the entries suppressed since the last report through the given core, if the report interval elapsed.
+// The suppressed entries of the modules not logging warnings are counted but not logged.
+func (s *sampler) report(core zapcore.Core) {
+	now := s.clock.Now()
+	last := s.lastReport.Load()
+	if now.UnixNano()-last < s.config.reportInterval.Load().Nanoseconds() ||
+		!s.lastReport.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	s.suppressed.Range(func(key, value any) bool {
+		k, sc := key.(suppressedKey), value.(*suppressedCount)
+		count := sc.count.Load()
+		suppressed := count - sc.reported
+		if suppressed == 0 {
+			return true
+		}
+		sc.reported = count
+		if levelWarn < s.level(k.module) {
+			return true
+		}
+		_ = core.Write(zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			Time:       now,
+			LoggerName: k.module,
+			Message:    "Log entries suppressed by sampling",
+		}, []zapcore.Field{
+			zap.String("level", k.level.CapitalString()),
+			zap.Int64("suppressed", suppressed),
+		})
+		return true
+	})
+}
+
+// moduleSampling returns the sampling of the given module, which is either configured for the module itself or for
+// its closest parent, or the default one
+func (s *sampler) moduleSampling(module string) moduleSampling {
+	modules := s.loadModules()
+	for name := module; name != ""; {
+		if ms, ok := modules[name]; ok {
+			return ms
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return moduleSampling{first: uint64(max(s.config.first.Load(), 0)), thereafter: uint64(max(s.config.thereafter.Load(), 0))}
+}
+
+func (s *sampler) loadModules() map[string]moduleSampling {
+	modulesConfig := s.config.modules.Load()
+	if loaded := s.modules.Load(); loaded != nil && loaded.config == modulesConfig {
+		return loaded.modules
+	}
+	loaded := &loadedModuleSampling{config: modulesConfig, modules: parseModuleSampling(modulesConfig)}
+	s.modules.Store(loaded)
+	return loaded.modules
+}
+
+// loadedModuleSampling is the sampling of the modules last parsed from the configuration
+type loadedModuleSampling struct {
+	config  string
+	modules map[string]moduleSampling
+}
+
+// samplingCounter counts entries during an interval, like the counters of zapcore.NewSamplerWithOptions
+type samplingCounter struct {
+	resetAt atomic.Int64
+	counter atomic.Uint64
+}
+
+func (c *samplingCounter) incCheckReset(t time.Time, interval time.Duration) uint64 {
+	tn := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > tn {
+		return c.counter.Add(1)
+	}
+	c.counter.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, tn+interval.Nanoseconds()) {
+		// another goroutine reset the counter concurrently
+		return c.counter.Add(1)
+	}
+	return 1
+}
+
+// samplingCore is a zapcore.Core sampling the entries it logs, see sampler
+type samplingCore struct {
+	zapcore.Core
+	sampler *sampler
+	root    zapcore.Core // the core suppressed entries are reported through, without any fields
+}
+
+func newSamplingCore(core zapcore.Core, sampler *sampler) zapcore.Core {
+	return &samplingCore{Core: core, sampler: sampler, root: core}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler, root: c.root}
+}
+
+func (c *samplingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.sampler.config.enabled.Load() {
+		return c.Core.Check(entry, checked)
+	}
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+	sampled := c.sampler.sample(entry)
+	c.sampler.report(c.root)
+	if !sampled {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,215 @@
+package logger_test
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestSampling(t *testing.T) {
+	newFactory := func(t *testing.T, clock *mockClock, options ...logger.Option) (*config.Config, *logger.Factory, func() []string) {
+		fileName := t.TempDir() + "out.log"
+		c := config.New()
+		c.Set("Logger.enableTimestamp", false)
+		c.Set("Logger.enableConsole", false)
+		c.Set("Logger.enableFile", true)
+		c.Set("Logger.enableFileNameInLog", false)
+		c.Set("Logger.logFileLocation", fileName)
+		c.Set("Logger.sampling.enabled", true)
+		c.Set("Logger.sampling.first", 2)
+		c.Set("Logger.sampling.thereafter", 3)
+		f := logger.NewFactory(c, append(options, logger.WithClock(clock))...)
+		t.Cleanup(func() { _ = f.Close() })
+		return c, f, func() []string {
+			out, err := os.ReadFile(fileName)
+			require.NoError(t, err)
+			return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		}
+	}
+
+	t.Run("first then thereafter", func(t *testing.T) {
+		clock := newMockClock()
+		_, f, lines := newFactory(t, clock)
+		log := f.NewLogger().Child("module")
+		for i := 0; i < 8; i++ {
+			log.Infow("hello", "i", i)
+			log.Warn("hello")
+		}
+		clock.Add(time.Second) // next interval
+		log.Infow("hello", "i", 8)
+
+		require.Equal(t, []string{
+			`INFO	module	hello	{"i": 0}`,
+			`WARN	module	hello`,
+			`INFO	module	hello	{"i": 1}`,
+			`WARN	module	hello`,
+			`INFO	module	hello	{"i": 4}`,
+			`WARN	module	hello`,
+			`INFO	module	hello	{"i": 7}`,
+			`WARN	module	hello`,
+			`INFO	module	hello	{"i": 8}`,
+		}, lines())
+	})
+
+	t.Run("module overrides", func(t *testing.T) {
+		clock := newMockClock()
+		c, f, lines := newFactory(t, clock)
+		c.Set("Logger.sampling.modules", "router=1/0:router.GA=off:invalid")
+		router := f.NewLogger().Child("router")
+		for i := 0; i < 3; i++ {
+			router.Child("GA").Info("ga")
+			router.Child("AM").Info("am")
+		}
+		require.Equal(t, []string{
+			"INFO	router.GA	ga",
+			"INFO	router.AM	am",
+			"INFO	router.GA	ga",
+			"INFO	router.GA	ga",
+		}, lines())
+	})
+
+	t.Run("hot reload", func(t *testing.T) {
+		clock := newMockClock()
+		c, f, lines := newFactory(t, clock)
+		c.Set("Logger.sampling.enabled", false)
+		log := f.NewLogger()
+		for i := 0; i < 3; i++ {
+			log.Info("hello")
+		}
+		require.Len(t, lines(), 3)
+
+		c.Set("Logger.sampling.enabled", true)
+		for i := 0; i < 3; i++ {
+			log.Info("hello")
+		}
+		require.Len(t, lines(), 5)
+	})
+
+	t.Run("suppressed entries", func(t *testing.T) {
+		clock := newMockClock()
+		counters := &mockCounters{counts: make(map[string]int)}
+		c, f, lines := newFactory(t, clock, logger.WithCounters(counters.new))
+		c.Set("Logger.sampling.reportInterval", "10s")
+		log := f.NewLogger().Child("module")
+		for i := 0; i < 5; i++ {
+			log.Info("hello")
+			log.Warn("hello")
+		}
+		require.Equal(t, map[string]int{
+			"logger_entries,level=INFO,module=module":            3,
+			"logger_entries,level=WARN,module=module":            3,
+			"logger_suppressed_entries,level=INFO,module=module": 2,
+			"logger_suppressed_entries,level=WARN,module=module": 2,
+		}, counters.get())
+
+		clock.Add(10 * time.Second)
+		log.Info("other")
+		clock.Add(10 * time.Second)
+		log.Info("other")
+		require.ElementsMatch(t, []string{
+			"INFO	module	hello",
+			"WARN	module	hello",
+			"INFO	module	hello",
+			"WARN	module	hello",
+			"INFO	module	hello",
+			"WARN	module	hello",
+			`WARN	module	Log entries suppressed by sampling	{"level": "INFO", "suppressed": 2}`,
+			`WARN	module	Log entries suppressed by sampling	{"level": "WARN", "suppressed": 2}`,
+			"INFO	module	other",
+			"INFO	module	other",
+		}, lines(), "suppressed entries are reported once")
+
+		// reports follow the level of the module
+		for i := 0; i < 3; i++ {
+			log.Info("hello")
+		}
+		require.NoError(t, f.SetLogLevel("module", "ERROR"))
+		clock.Add(10 * time.Second)
+		log.Error("error")
+		require.Equal(t, 3, counters.get()["logger_suppressed_entries,level=INFO,module=module"])
+		require.Equal(t, "ERROR	module	error", lines()[len(lines())-1])
+		require.Len(t, lines(), 13)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		clock := newMockClock()
+		_, f, lines := newFactory(t, clock)
+		log := f.NewLogger().Child("module")
+		for i := 0; i < 5; i++ {
+			log.Error("error")
+			log.Fatal("fatal")
+		}
+		var logged []string
+		for _, line := range lines() {
+			if line == "ERROR	module	error" || line == "ERROR	module	fatal" {
+				logged = append(logged, line)
+			}
+		}
+		require.Len(t, logged, 10, "errors and fatal entries are never sampled")
+	})
+}
+
+type mockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newMockClock() *mockClock {
+	return &mockClock{now: time.Date(2077, 1, 23, 10, 15, 13, 0, time.UTC)}
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (*mockClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+type mockCounters struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *mockCounters) new(name string, tags map[string]string) logger.Counter {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	key := name
+	for _, k := range keys {
+		key += "," + k + "=" + tags[k]
+	}
+	return counterFunc(func(n int) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.counts[key] += n
+	})
+}
+
+func (m *mockCounters) get() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts
+}
+
+type counterFunc func(n int)
+
+func (f counterFunc) Count(n int) { f(n) }
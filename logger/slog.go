@@ -0,0 +1,319 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSlogHandler returns a slog.Handler logging through the given logger, e.g. for passing
+// slog.New(logger.NewSlogHandler(log.Child("kafka"))) to a third-party library.
+//
+// Records are logged with the level, name and context of the logger, being enabled according to the level of the
+// logger's module. Levels below slog.LevelInfo are logged as DEBUG, while levels above slog.LevelError as ERROR.
+// Groups are flattened, i.e. attribute "b" of group "a" is logged as field "a.b".
+func NewSlogHandler(l Logger) slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+type slogHandler struct {
+	logger Logger
+	group  string // the prefix of the keys of the attributes, e.g. "a.b." if within group "b" of group "a"
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if l, ok := h.logger.(*logger); ok {
+		return fromSlogLevel(level) >= l.getLoggingLevel()
+	}
+	if level < slog.LevelInfo {
+		return h.logger.IsDebugLevel()
+	}
+	return true
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.group, a)
+		return true
+	})
+	lg := h.logger
+	if ctx != nil {
+		lg = lg.WithContext(ctx)
+	}
+
+	level := fromSlogLevel(r.Level)
+	l, ok := lg.(*logger)
+	if !ok {
+		switch level {
+		case levelDebug:
+			lg.Debugn(r.Message, fields...)
+		case levelInfo:
+			lg.Infon(r.Message, fields...)
+		case levelWarn:
+			lg.Warnn(r.Message, fields...)
+		default:
+			lg.Errorn(r.Message, fields...)
+		}
+		return nil
+	}
+
+	if level < l.getLoggingLevel() {
+		return nil
+	}
+	ce := l.zap.Check(toZapLevel(level), r.Message)
+	if ce == nil {
+		return nil
+	}
+	if !r.Time.IsZero() {
+		ce.Time = r.Time
+	}
+	if ce.Caller.Defined && r.PC != 0 {
+		// the caller is the one of the record, rather than the handler
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ce.Caller = zapcore.EntryCaller{Defined: true, PC: frame.PC, File: frame.File, Line: frame.Line, Function: frame.Function}
+	}
+	ce.Write(toZap(fields)...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, h.group, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	return &slogHandler{logger: h.logger.Withn(fields...), group: h.group}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger, group: h.group + name + "."}
+}
+
+// appendSlogAttr appends the attribute as a field, or the attributes of a group as fields whose keys are prefixed by the
+// group name, following the rules of slog.Handler, e.g. empty attributes are ignored
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	key := prefix + a.Key
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(fields, NewStringField(key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, NewIntField(key, a.Value.Int64()))
+	case slog.KindBool:
+		return append(fields, NewBoolField(key, a.Value.Bool()))
+	case slog.KindFloat64:
+		return append(fields, NewFloatField(key, a.Value.Float64()))
+	case slog.KindTime:
+		return append(fields, NewTimeField(key, a.Value.Time()))
+	case slog.KindDuration:
+		return append(fields, NewDurationField(key, a.Value.Duration()))
+	case slog.KindGroup:
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	default:
+		return append(fields, NewField(key, a.Value.Any()))
+	}
+}
+
+// fromSlogLevel returns the level the given slog level is logged at
+func fromSlogLevel(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return levelDebug
+	case level < slog.LevelWarn:
+		return levelInfo
+	case level < slog.LevelError:
+		return levelWarn
+	default:
+		return levelError
+	}
+}
+
+func toZapLevel(level int) zapcore.Level {
+	switch level {
+	case levelEvent, levelDebug:
+		return zapcore.DebugLevel
+	case levelInfo:
+		return zapcore.InfoLevel
+	case levelWarn:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// FromSlog returns a Logger logging through the given slog logger, e.g. for passing a *slog.Logger to a component
+// requiring a Logger. Child names are logged in the "logger" attribute, e.g. logger=router.GA, and Fatal entries
+// are logged at slog.LevelError along with their stack trace, never exiting the process.
+func FromSlog(l *slog.Logger) Logger {
+	return &slogLogger{slog: l}
+}
+
+type slogLogger struct {
+	slog *slog.Logger
+	name string
+}
+
+// log logs a record through the slog logger, args being a mix of key-value pairs and slog.Attr values as for
+// slog.Logger.Log. Skip is the number of stack frames to skip for reporting the caller of the Logger method as the
+// source, 0 identifying the caller of log.
+func (l *slogLogger) log(skip int, level slog.Level, msg string, args ...any) {
+	ctx := context.Background()
+	if !l.slog.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:]) // skip runtime.Callers and log
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if l.name != "" {
+		r.AddAttrs(slog.String("logger", l.name))
+	}
+	r.Add(args...)
+	_ = l.slog.Handler().Handle(ctx, r)
+}
+
+func (l *slogLogger) fatal(msg string, args ...any) {
+	stack := make([]byte, 2048)
+	stack = stack[:runtime.Stack(stack, false)]
+	l.log(2, slog.LevelError, msg, append(args, slog.String("stacktrace", string(stack)))...)
+}
+
+func toSlogArgs(fields []Field) []any {
+	args := make([]any, len(fields))
+	for i, f := range fields {
+		args[i] = slog.Any(f.name, f.Value())
+	}
+	return args
+}
+
+func (l *slogLogger) IsDebugLevel() bool {
+	return l.slog.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func (l *slogLogger) Debug(args ...any) { l.log(1, slog.LevelDebug, fmt.Sprint(args...)) }
+func (l *slogLogger) Info(args ...any)  { l.log(1, slog.LevelInfo, fmt.Sprint(args...)) }
+func (l *slogLogger) Warn(args ...any)  { l.log(1, slog.LevelWarn, fmt.Sprint(args...)) }
+func (l *slogLogger) Error(args ...any) { l.log(1, slog.LevelError, fmt.Sprint(args...)) }
+func (l *slogLogger) Fatal(args ...any) { l.fatal(fmt.Sprint(args...)) }
+
+func (l *slogLogger) Debugf(format string, args ...any) {
+	l.log(1, slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Infof(format string, args ...any) {
+	l.log(1, slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Warnf(format string, args ...any) {
+	l.log(1, slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Errorf(format string, args ...any) {
+	l.log(1, slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Fatalf(format string, args ...any) { l.fatal(fmt.Sprintf(format, args...)) }
+
+func (l *slogLogger) Debugw(msg string, keysAndValues ...any) {
+	l.log(1, slog.LevelDebug, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Infow(msg string, keysAndValues ...any) {
+	l.log(1, slog.LevelInfo, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Warnw(msg string, keysAndValues ...any) {
+	l.log(1, slog.LevelWarn, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Errorw(msg string, keysAndValues ...any) {
+	l.log(1, slog.LevelError, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Fatalw(msg string, keysAndValues ...any) { l.fatal(msg, keysAndValues...) }
+
+func (l *slogLogger) Debugn(msg string, fields ...Field) {
+	l.log(1, slog.LevelDebug, msg, toSlogArgs(fields)...)
+}
+
+func (l *slogLogger) Infon(msg string, fields ...Field) {
+	l.log(1, slog.LevelInfo, msg, toSlogArgs(fields)...)
+}
+
+func (l *slogLogger) Warnn(msg string, fields ...Field) {
+	l.log(1, slog.LevelWarn, msg, toSlogArgs(fields)...)
+}
+
+func (l *slogLogger) Errorn(msg string, fields ...Field) {
+	l.log(1, slog.LevelError, msg, toSlogArgs(fields)...)
+}
+
+func (l *slogLogger) Fataln(msg string, fields ...Field) { l.fatal(msg, toSlogArgs(fields)...) }
+
+func (l *slogLogger) LogRequest(req *http.Request) {
+	if !l.IsDebugLevel() {
+		return
+	}
+	defer func() { _ = req.Body.Close() }()
+	bodyBytes, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	args := []any{slog.String("body", string(bodyBytes))}
+	if len(req.Header) > 0 {
+		args = append(args, slog.Any("headers", redactHeaders(req.Header, func(string) string { return redactedMask })))
+	}
+	l.log(1, slog.LevelDebug, "Request Body", args...)
+}
+
+func (l *slogLogger) Child(s string) Logger {
+	if s == "" {
+		return l
+	}
+	cp := *l
+	if l.name == "" {
+		cp.name = s
+	} else {
+		cp.name = l.name + "." + s
+	}
+	return &cp
+}
+
+func (l *slogLogger) With(args ...any) Logger {
+	cp := *l
+	cp.slog = l.slog.With(args...)
+	return &cp
+}
+
+func (l *slogLogger) Withn(fields ...Field) Logger {
+	cp := *l
+	cp.slog = l.slog.With(toSlogArgs(fields)...)
+	return &cp
+}
+
+func (l *slogLogger) WithContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.Withn(fields...)
+}
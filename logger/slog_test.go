@@ -0,0 +1,104 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestSlogHandler(t *testing.T) {
+	fileName := t.TempDir() + "out.log"
+	c := config.New()
+	c.Set("LOG_LEVEL", "INFO")
+	c.Set("Logger.moduleLevels", "lib.kafka=DEBUG")
+	c.Set("Logger.enableTimestamp", false)
+	c.Set("Logger.enableConsole", false)
+	c.Set("Logger.enableFile", true)
+	c.Set("Logger.logFileLocation", fileName)
+	loggerFactory := logger.NewFactory(c)
+	t.Cleanup(func() { _ = loggerFactory.Close() })
+	lib := loggerFactory.NewLogger().Child("lib")
+
+	s := slog.New(logger.NewSlogHandler(lib))
+	s.Debug("not logged")
+	s.Info("info", "n", 1, slog.Group("req", "id", "req-1", slog.Group("user", "id", 2)), slog.Group("empty"))
+	s.WithGroup("g").With("a", true).Warn("warn", "d", time.Second, "f", 1.5, "e", errors.New("boom"))
+
+	kafka := slog.New(logger.NewSlogHandler(lib.Child("kafka")))
+	kafka.Debug("debug", "u", uint64(1))
+	kafka.Log(context.Background(), slog.LevelError+4, "above error")
+	require.True(t, kafka.Enabled(context.Background(), slog.LevelDebug-4))
+
+	fileOut, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(fileOut), "\n"), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, []string{"INFO", "lib", "logger/slog_test.go:35", "info", `{"n": 1, "req.id": "req-1", "req.user.id": 2}`}, strings.Split(lines[0], "\t"))
+	require.Equal(t, []string{"WARN", "lib", "logger/slog_test.go:36", "warn", `{"g.a": true, "g.d": 1, "g.f": 1.5, "g.e": "boom"}`}, strings.Split(lines[1], "\t"))
+	require.Equal(t, []string{"DEBUG", "lib.kafka", "logger/slog_test.go:39", "debug", `{"u": 1}`}, strings.Split(lines[2], "\t"))
+	require.Equal(t, []string{"ERROR", "lib.kafka", "logger/slog_test.go:40", "above error"}, strings.Split(lines[3], "\t"))
+}
+
+func TestSlogHandlerNonZapLogger(t *testing.T) {
+	tf := logger.NewTestFactory(t, logger.WithErrorsAllowed())
+	s := slog.New(logger.NewSlogHandler(logger.FromSlog(slog.New(logger.NewSlogHandler(tf.NewLogger())))))
+	s.Info("info", "n", 1)
+	s.Error("error")
+	require.Equal(t, []string{"info", "error"}, tf.Entries().Messages())
+	require.Equal(t, int64(1), tf.Entries()[0].Fields["n"])
+}
+
+func TestFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	s := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch {
+			case a.Key == slog.TimeKey && len(groups) == 0:
+				return slog.Attr{}
+			case a.Key == slog.SourceKey:
+				// the function is enough for checking that the caller is reported, rather than the adapter
+				function := a.Value.Any().(*slog.Source).Function
+				return slog.String(slog.SourceKey, function[strings.LastIndexByte(function, '.')+1:])
+			case a.Key == "stacktrace":
+				return slog.Bool("stacktrace", a.Value.String() != "")
+			}
+			return a
+		},
+	}))
+	log := logger.FromSlog(s)
+	require.False(t, log.IsDebugLevel())
+
+	log.Debug("not logged")
+	log.Infof("hello %s", "world")
+	child := log.Child("router").Child("GA").With("k", "v")
+	child.Warnw("warn", "n", 1)
+	child.Withn(logger.NewIntField("m", 2)).Errorn("error", logger.NewSecretField("token", "abc"))
+	child.Fatal("fatal")
+
+	ctx := logger.ContextWithFields(context.Background(), logger.NewStringField("requestId", "req-1"))
+	log.WithContext(ctx).Info("with context")
+
+	request, err := http.NewRequest(http.MethodPost, "https://example.com", strings.NewReader("{}"))
+	require.NoError(t, err)
+	request.Header.Set("Cookie", "session=abc")
+	log.LogRequest(request)
+	require.Equal(t, `level=INFO source=TestFromSlog msg="hello world"
+level=WARN source=TestFromSlog msg=warn k=v logger=router.GA n=1
+level=ERROR source=TestFromSlog msg=error k=v m=2 logger=router.GA token=***
+level=ERROR source=TestFromSlog msg=fatal k=v logger=router.GA stacktrace=true
+level=INFO source=TestFromSlog msg="with context" requestId=req-1
+`, buf.String(), "request bodies are logged at DEBUG level")
+}
@@ -0,0 +1,285 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+// TestFactory is a logger factory for tests, capturing the entries logged by its loggers in memory. See NewTestFactory.
+type TestFactory struct {
+	*Factory
+	core *testCore
+}
+
+// TestEntry is an entry captured by a TestFactory
+type TestEntry struct {
+	Level      string         // the level of the entry, e.g. INFO
+	LoggerName string         // the name of the logger, e.g. router.GA
+	Message    string         // the message of the entry
+	Fields     map[string]any // the fields of the entry, including the ones of the logger context
+	Stack      string         // the stack trace of the entry, if any
+	Fatal      bool           // whether the entry was logged through Logger.Fatal and alike, its level being ERROR
+}
+
+// TestEntries are entries captured by a TestFactory, in the order they were logged
+type TestEntries []TestEntry
+
+// TestOption configures a TestFactory
+type TestOption func(*testFactoryConfig)
+
+type testFactoryConfig struct {
+	config      *config.Config
+	log         bool
+	failOnError bool
+	options     []Option
+}
+
+// WithTestConfig specifies the configuration of the TestFactory, e.g. for setting module levels.
+// Defaults to an empty configuration with LOG_LEVEL set to DEBUG.
+// Console and file outputs are always disabled.
+func WithTestConfig(c *config.Config) TestOption {
+	return func(tc *testFactoryConfig) {
+		tc.config = c
+	}
+}
+
+// WithoutTestLog disables forwarding the entries to testing.TB.Log
+func WithoutTestLog() TestOption {
+	return func(tc *testFactoryConfig) {
+		tc.log = false
+	}
+}
+
+// WithErrorsAllowed disables failing the test when an entry with a level of ERROR or above is logged,
+// see TestFactory.ExpectErrors
+func WithErrorsAllowed() TestOption {
+	return func(tc *testFactoryConfig) {
+		tc.failOnError = false
+	}
+}
+
+// WithTestFactoryOptions specifies the options of the underlying Factory, e.g. WithClock
+func WithTestFactoryOptions(options ...Option) TestOption {
+	return func(tc *testFactoryConfig) {
+		tc.options = append(tc.options, options...)
+	}
+}
+
+// NewTestFactory creates a logger factory for tests, capturing the entries logged by its loggers in memory, see
+// TestFactory.Entries. Entries are also forwarded to t.Log, unless WithoutTestLog is provided.
+//
+// The test fails if an entry with a level of ERROR or above is logged without being expected (see
+// TestFactory.ExpectErrors), unless WithErrorsAllowed is provided. Calls to Logger.Fatal are captured as ERROR
+// entries marked as fatal along with their stack trace, see TestEntries.Fatal.
+func NewTestFactory(t testing.TB, options ...TestOption) *TestFactory {
+	t.Helper()
+	tc := testFactoryConfig{log: true, failOnError: true}
+	for _, option := range options {
+		option(&tc)
+	}
+	c := tc.config
+	if c == nil {
+		c = config.New()
+		c.Set("LOG_LEVEL", "DEBUG")
+	}
+	c.Set("Logger.enableConsole", false)
+	c.Set("Logger.enableFile", false)
+	c.Set("Logger.enableStackTrace", true) // Fatal entries come with their stack trace, rather than a separate entry
+
+	core := &testCore{
+		LevelEnabler: zapcore.DebugLevel,
+		t:            t,
+		log:          tc.log,
+		failOnError:  tc.failOnError,
+		encoder:      zapEncoder(c, false),
+		shared:       &testCoreShared{},
+	}
+	t.Cleanup(func() {
+		core.shared.mu.Lock()
+		defer core.shared.mu.Unlock()
+		core.shared.done = true
+	})
+	factoryOptions := append([]Option{optionFunc(func(f *Factory) {
+		f.config.cores = append(f.config.cores, core)
+	})}, tc.options...)
+	tf := &TestFactory{Factory: NewFactory(c, factoryOptions...), core: core}
+	t.Cleanup(func() { _ = tf.Close() })
+	return tf
+}
+
+// Entries returns the entries captured so far
+func (tf *TestFactory) Entries() TestEntries {
+	tf.core.shared.mu.Lock()
+	defer tf.core.shared.mu.Unlock()
+	return append(TestEntries(nil), tf.core.shared.entries...)
+}
+
+// Reset discards the entries captured so far
+func (tf *TestFactory) Reset() {
+	tf.core.shared.mu.Lock()
+	defer tf.core.shared.mu.Unlock()
+	tf.core.shared.entries = nil
+}
+
+// ExpectErrors makes entries with a level of ERROR or above and a message matching the regular expression not fail the
+// test
+func (tf *TestFactory) ExpectErrors(pattern string) {
+	re := regexp.MustCompile(pattern)
+	tf.core.shared.mu.Lock()
+	defer tf.core.shared.mu.Unlock()
+	tf.core.shared.expected = append(tf.core.shared.expected, re)
+}
+
+// Level returns the entries with the given level, e.g. INFO
+func (e TestEntries) Level(level string) TestEntries {
+	return e.Filter(func(entry TestEntry) bool { return entry.Level == strings.ToUpper(level) })
+}
+
+// Fatal returns the entries logged through Logger.Fatal and alike
+func (e TestEntries) Fatal() TestEntries {
+	return e.Filter(func(entry TestEntry) bool { return entry.Fatal })
+}
+
+// LoggerName returns the entries logged by the logger with the given name, e.g. router.GA
+func (e TestEntries) LoggerName(name string) TestEntries {
+	return e.Filter(func(entry TestEntry) bool { return entry.LoggerName == name })
+}
+
+// Message returns the entries with the given message
+func (e TestEntries) Message(msg string) TestEntries {
+	return e.Filter(func(entry TestEntry) bool { return entry.Message == msg })
+}
+
+// MessageContains returns the entries with a message containing the given substring
+func (e TestEntries) MessageContains(s string) TestEntries {
+	return e.Filter(func(entry TestEntry) bool { return strings.Contains(entry.Message, s) })
+}
+
+// Field returns the entries having a field with the given key and value. Values are compared through their string
+// representation, e.g. an int64 field with value 1 matches both 1 and "1".
+func (e TestEntries) Field(key string, value any) TestEntries {
+	return e.Filter(func(entry TestEntry) bool {
+		v, ok := entry.Fields[key]
+		return ok && fmt.Sprint(v) == fmt.Sprint(value)
+	})
+}
+
+// Filter returns the entries for which the function returns true
+func (e TestEntries) Filter(f func(TestEntry) bool) TestEntries {
+	var res TestEntries
+	for _, entry := range e {
+		if f(entry) {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
+
+// Messages returns the messages of the entries
+func (e TestEntries) Messages() []string {
+	res := make([]string, len(e))
+	for i, entry := range e {
+		res[i] = entry.Message
+	}
+	return res
+}
+
+// testCore is a zapcore.Core capturing the entries of a TestFactory
+type testCore struct {
+	zapcore.LevelEnabler
+	t           testing.TB
+	log         bool
+	failOnError bool
+	encoder     zapcore.Encoder
+	fields      []zapcore.Field // the fields of the logger context
+	shared      *testCoreShared
+}
+
+// testCoreShared is the state shared by a testCore and the ones derived from it through With
+type testCoreShared struct {
+	mu       sync.Mutex
+	entries  TestEntries
+	expected []*regexp.Regexp
+	done     bool // whether the test completed, after which it can't be logged to or failed anymore
+}
+
+func (c *testCore) With(fields []zapcore.Field) zapcore.Core {
+	cp := *c
+	cp.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	cp.encoder = c.encoder.Clone()
+	for _, f := range fields {
+		f.AddTo(cp.encoder)
+	}
+	return &cp
+}
+
+func (c *testCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *testCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var fatal bool
+	for _, f := range slices.Concat(c.fields, fields) {
+		if f.Equals(fatalMarker) {
+			fatal = true
+		}
+		f.AddTo(enc)
+	}
+	testEntry := TestEntry{
+		Level:      entry.Level.CapitalString(),
+		LoggerName: entry.LoggerName,
+		Message:    entry.Message,
+		Fields:     enc.Fields,
+		Stack:      entry.Stack,
+		Fatal:      fatal,
+	}
+
+	c.shared.mu.Lock()
+	defer c.shared.mu.Unlock()
+	c.shared.entries = append(c.shared.entries, testEntry)
+	if c.shared.done {
+		return nil
+	}
+	if c.log {
+		buf, err := c.encoder.EncodeEntry(entry, fields)
+		if err != nil {
+			return err
+		}
+		c.t.Log(strings.TrimSuffix(buf.String(), "\n"))
+		buf.Free()
+	}
+	if c.failOnError && entry.Level >= zapcore.ErrorLevel && !c.expected(entry.Message) {
+		if fatal {
+			c.t.Errorf("unexpected fatal %s entry logged: %s", testEntry.Level, entry.Message)
+		} else {
+			c.t.Errorf("unexpected %s entry logged: %s", testEntry.Level, entry.Message)
+		}
+	}
+	return nil
+}
+
+// expected returns true if the message matches an expected error, the caller holding the lock
+func (c *testCore) expected(msg string) bool {
+	for _, re := range c.shared.expected {
+		if re.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (*testCore) Sync() error {
+	return nil
+}
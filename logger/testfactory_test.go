@@ -0,0 +1,118 @@
+package logger_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestTestFactory(t *testing.T) {
+	t.Run("captures entries", func(t *testing.T) {
+		mt := &mockT{TB: t}
+		tf := logger.NewTestFactory(mt)
+		log := tf.NewLogger().Child("router").With("workspaceId", "ws-1")
+		log.Debugw("debug", "n", 1)
+		log.Child("GA").Infon("info", logger.NewIntField("n", 2), logger.NewSecretField("token", "abc"))
+		log.Warnf("warn %d", 3)
+
+		entries := tf.Entries()
+		require.Equal(t, []string{"debug", "info", "warn 3"}, entries.Messages())
+		require.Equal(t, logger.TestEntry{
+			Level:      "INFO",
+			LoggerName: "router.GA",
+			Message:    "info",
+			Fields:     map[string]any{"workspaceId": "ws-1", "n": int64(2), "token": "***"},
+		}, entries[1])
+		require.Equal(t, []string{"warn 3"}, entries.Level("warn").Messages())
+		require.Equal(t, []string{"debug", "warn 3"}, entries.LoggerName("router").Messages())
+		require.Equal(t, []string{"info"}, entries.Field("n", 2).Messages())
+		require.Equal(t, []string{"warn 3"}, entries.MessageContains("3").Messages())
+		require.Len(t, entries.Message("debug").Field("workspaceId", "ws-1"), 1)
+		require.Len(t, mt.logs, 3, "entries are forwarded to t.Log")
+		require.Contains(t, mt.logs[1], "\tINFO\trouter.GA\t")
+		require.Contains(t, mt.logs[1], "\tinfo\t{\"workspaceId\": \"ws-1\", \"n\": 2, \"token\": \"***\"}")
+		require.False(t, mt.failed)
+
+		tf.Reset()
+		require.Empty(t, tf.Entries())
+	})
+
+	t.Run("fails on unexpected errors", func(t *testing.T) {
+		mt := &mockT{TB: t}
+		tf := logger.NewTestFactory(mt, logger.WithoutTestLog())
+		log := tf.NewLogger()
+		tf.ExpectErrors("^expected")
+		log.Errorw("expected failure", "error", errors.New("boom"))
+		require.False(t, mt.failed)
+		log.Error("unexpected failure")
+		require.True(t, mt.failed)
+		require.Equal(t, []string{"unexpected ERROR entry logged: unexpected failure"}, mt.errors)
+		require.Empty(t, mt.logs)
+	})
+
+	t.Run("intercepts fatal", func(t *testing.T) {
+		mt := &mockT{TB: t}
+		tf := logger.NewTestFactory(mt, logger.WithoutTestLog())
+		log := tf.NewLogger().With("k", "v")
+		log.Fatalf("fatal %s", "failure")
+		log.Error("error")
+		log.Fatal("fatal")
+		log.Fatalw("fatalw", "n", 1)
+		log.Fataln("fataln", logger.NewIntField("n", 2))
+		log.Error("error")
+		entries := tf.Entries()
+		require.Len(t, entries, 6)
+		require.Equal(t, "fatal failure", entries[0].Message)
+		require.Equal(t, "ERROR", entries[0].Level)
+		require.NotEmpty(t, entries[0].Stack)
+		require.True(t, entries[0].Fatal)
+		require.Equal(t, map[string]any{"k": "v"}, entries[0].Fields, "the marker isn't a field")
+		require.False(t, entries[1].Fatal)
+		require.Equal(t, []string{"fatal failure", "fatal", "fatalw", "fataln"}, entries.Fatal().Messages())
+		require.Equal(t, map[string]any{"k": "v", "n": int64(2)}, entries.Fatal()[3].Fields)
+		require.True(t, mt.failed)
+		require.Equal(t, "unexpected fatal ERROR entry logged: fatal failure", mt.errors[0])
+	})
+
+	t.Run("errors allowed", func(t *testing.T) {
+		mt := &mockT{TB: t}
+		c := config.New()
+		c.Set("LOG_LEVEL", "WARN")
+		tf := logger.NewTestFactory(mt, logger.WithErrorsAllowed(), logger.WithTestConfig(c))
+		log := tf.NewLogger()
+		log.Info("info")
+		log.Error("error")
+		require.Equal(t, []string{"error"}, tf.Entries().Messages())
+		require.False(t, mt.failed)
+	})
+}
+
+// mockT is a testing.TB recording logs and failures instead of logging to and failing the test
+type mockT struct {
+	testing.TB
+	mu     sync.Mutex
+	failed bool
+	errors []string
+	logs   []string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Log(args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, fmt.Sprint(args...))
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failed = true
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,61 @@
+package redisutil
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	redisUniqName = "redis_pool_%s"
+)
+
+// Pool is the interface implemented by redis.Client, redis.ClusterClient and redis.Ring
+type Pool interface {
+	PoolStats() *redis.PoolStats
+}
+
+// PoolCollector collects the statistics of the connection pool of a go-redis client, tagged by name
+type PoolCollector struct {
+	name string
+	pool Pool
+}
+
+// NewPoolCollector creates a new collector for the connection pool of a go-redis client
+func NewPoolCollector(name string, pool Pool) *PoolCollector {
+	return &PoolCollector{
+		name: name,
+		pool: pool,
+	}
+}
+
+func (s *PoolCollector) Collect(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	poolStats := s.pool.PoolStats()
+	tags := stats.Tags{"name": s.name}
+
+	gaugeFunc("redis_pool_hits_total", tags, uint64(poolStats.Hits))
+	gaugeFunc("redis_pool_misses_total", tags, uint64(poolStats.Misses))
+	gaugeFunc("redis_pool_timeouts_total", tags, uint64(poolStats.Timeouts))
+
+	gaugeFunc("redis_pool_total_connections", tags, uint64(poolStats.TotalConns))
+	gaugeFunc("redis_pool_idle_connections", tags, uint64(poolStats.IdleConns))
+	gaugeFunc("redis_pool_stale_connections_total", tags, uint64(poolStats.StaleConns))
+}
+
+func (s *PoolCollector) Zero(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	tags := stats.Tags{"name": s.name}
+
+	gaugeFunc("redis_pool_hits_total", tags, 0)
+	gaugeFunc("redis_pool_misses_total", tags, 0)
+	gaugeFunc("redis_pool_timeouts_total", tags, 0)
+
+	gaugeFunc("redis_pool_total_connections", tags, 0)
+	gaugeFunc("redis_pool_idle_connections", tags, 0)
+	gaugeFunc("redis_pool_stale_connections_total", tags, 0)
+}
+
+func (s *PoolCollector) ID() string {
+	return fmt.Sprintf(redisUniqName, s.name)
+}
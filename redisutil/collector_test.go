@@ -0,0 +1,43 @@
+package redisutil_test
+
+import (
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/redisutil"
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+type mockRedisPool struct{ stats redis.PoolStats }
+
+func (m *mockRedisPool) PoolStats() *redis.PoolStats { return &m.stats }
+
+func TestPoolCollector(t *testing.T) {
+	m, err := memstats.New()
+	require.NoError(t, err)
+
+	testName := "test_redis"
+	s := redisutil.NewPoolCollector(testName, &mockRedisPool{stats: redis.PoolStats{
+		Hits: 10, Misses: 2, Timeouts: 1, TotalConns: 5, IdleConns: 3, StaleConns: 4,
+	}})
+	require.Equal(t, "redis_pool_test_redis", s.ID())
+	require.NoError(t, m.RegisterCollector(s))
+
+	tags := stats.Tags{"name": testName}
+	require.ElementsMatch(t, []memstats.Metric{
+		{Name: "redis_pool_hits_total", Tags: tags, Value: 10},
+		{Name: "redis_pool_misses_total", Tags: tags, Value: 2},
+		{Name: "redis_pool_timeouts_total", Tags: tags, Value: 1},
+		{Name: "redis_pool_total_connections", Tags: tags, Value: 5},
+		{Name: "redis_pool_idle_connections", Tags: tags, Value: 3},
+		{Name: "redis_pool_stale_connections_total", Tags: tags, Value: 4},
+	}, m.GetAll())
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:1"})
+	defer func() { _ = client.Close() }()
+	require.NoError(t, m.RegisterCollector(redisutil.NewPoolCollector("client", client)))
+	require.EqualValues(t, 0, m.Get("redis_pool_total_connections", stats.Tags{"name": "client"}).LastValue())
+}
@@ -0,0 +1,100 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	tracerName = "redisutil"
+
+	// pipelineCommand is the command tag used for pipelines and transactions
+	pipelineCommand = "pipeline"
+)
+
+type hookStartKey struct{}
+
+type hookStart struct {
+	time time.Time
+	span stats.TraceSpan
+}
+
+// NewHook returns a go-redis hook that, for every command and pipeline:
+//   - records its latency with the "redis_command_duration" timer;
+//   - increments the "redis_command_errors" counter if it fails (redis.Nil is not considered an error);
+//   - creates a client span named "redis.<command>" (or "redis.pipeline") using the Tracer of statsFactory.
+//
+// All measurements are tagged with the identifier and the command name (or "pipeline"), e.g.
+//
+//	client := redis.NewClient(&redis.Options{Addr: addr})
+//	client.AddHook(redisutil.NewHook(stats.Default, "throttling"))
+func NewHook(statsFactory stats.Stats, identifier string) redis.Hook {
+	return &hook{
+		stats:      statsFactory,
+		tracer:     statsFactory.NewTracer(tracerName),
+		identifier: identifier,
+	}
+}
+
+type hook struct {
+	stats      stats.Stats
+	tracer     stats.Tracer
+	identifier string
+}
+
+func (h *hook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return h.start(ctx, cmd.Name(), stats.IntAttribute("db.redis.num_cmd", 1)), nil
+}
+
+func (h *hook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.end(ctx, cmd.Name(), []redis.Cmder{cmd})
+	return nil
+}
+
+func (h *hook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return h.start(ctx, pipelineCommand, stats.IntAttribute("db.redis.num_cmd", len(cmds))), nil
+}
+
+func (h *hook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	h.end(ctx, pipelineCommand, cmds)
+	return nil
+}
+
+func (h *hook) start(ctx context.Context, command string, attrs ...stats.Attribute) context.Context {
+	ctx, span := h.tracer.Start(ctx, "redis."+command, stats.SpanKindClient,
+		stats.SpanWithTags(stats.Tags{"db.system": "redis", "db.operation": command, "identifier": h.identifier}),
+		stats.SpanWithAttributes(attrs...),
+	)
+	return context.WithValue(ctx, hookStartKey{}, hookStart{time: time.Now(), span: span})
+}
+
+func (h *hook) end(ctx context.Context, command string, cmds []redis.Cmder) {
+	start, ok := ctx.Value(hookStartKey{}).(hookStart)
+	if !ok {
+		return
+	}
+	tags := stats.Tags{"identifier": h.identifier, "command": command}
+	h.stats.NewTaggedStat("redis_command_duration", stats.TimerType, tags).SinceCtx(ctx, start.time)
+
+	if err := firstError(cmds); err != nil {
+		h.stats.NewTaggedStat("redis_command_errors", stats.CountType, tags).Increment()
+		start.span.RecordError(err)
+		start.span.SetStatus(stats.SpanStatusError, err.Error())
+	}
+	start.span.End()
+}
+
+// firstError returns the first error of the given commands, ignoring redis.Nil replies
+func firstError(cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package redisutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/redisutil"
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+	"github.com/khulnasoft/go-kit/stats/testhelper/tracemodel"
+)
+
+func TestHook(t *testing.T) {
+	t.Run("commands", func(t *testing.T) {
+		store, err := memstats.New(memstats.WithTracing())
+		require.NoError(t, err)
+		hook := redisutil.NewHook(store, "test")
+
+		ok := redis.NewStringCmd(context.Background(), "get", "key")
+		ctx, err := hook.BeforeProcess(context.Background(), ok)
+		require.NoError(t, err)
+		require.NoError(t, hook.AfterProcess(ctx, ok))
+
+		nilReply := redis.NewStringCmd(context.Background(), "get", "missing")
+		nilReply.SetErr(redis.Nil)
+		ctx, err = hook.BeforeProcess(context.Background(), nilReply)
+		require.NoError(t, err)
+		require.NoError(t, hook.AfterProcess(ctx, nilReply))
+
+		failed := redis.NewStatusCmd(context.Background(), "set", "key", "value")
+		failed.SetErr(errors.New("READONLY You can't write against a read only replica"))
+		ctx, err = hook.BeforeProcess(context.Background(), failed)
+		require.NoError(t, err)
+		require.NoError(t, hook.AfterProcess(ctx, failed))
+
+		getTags := stats.Tags{"identifier": "test", "command": "get"}
+		setTags := stats.Tags{"identifier": "test", "command": "set"}
+		require.Len(t, store.Get("redis_command_duration", getTags).Durations(), 2)
+		require.Len(t, store.Get("redis_command_duration", setTags).Durations(), 1)
+		require.Nil(t, store.Get("redis_command_errors", getTags), "redis.Nil should not be counted as an error")
+		require.EqualValues(t, 1, store.Get("redis_command_errors", setTags).LastValue())
+
+		spans, err := store.Spans()
+		require.NoError(t, err)
+		require.Len(t, spans, 3)
+		require.Equal(t, "redis.get", spans[0].Name)
+		require.Equal(t, int(stats.SpanKindClient), spans[0].SpanKind)
+		require.Equal(t, "Unset", spans[1].Status.Code)
+		require.Equal(t, "redis.set", spans[2].Name)
+		require.Equal(t, "Error", spans[2].Status.Code)
+		require.Len(t, spans[2].Events, 1)
+		require.Equal(t, "exception", spans[2].Events[0].Name)
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		store, err := memstats.New(memstats.WithTracing())
+		require.NoError(t, err)
+		hook := redisutil.NewHook(store, "test")
+
+		cmds := []redis.Cmder{
+			redis.NewStringCmd(context.Background(), "get", "key"),
+			redis.NewIntCmd(context.Background(), "incr", "counter"),
+		}
+		cmds[1].SetErr(errors.New("ERR value is not an integer or out of range"))
+		ctx, err := hook.BeforeProcessPipeline(context.Background(), cmds)
+		require.NoError(t, err)
+		require.NoError(t, hook.AfterProcessPipeline(ctx, cmds))
+
+		tags := stats.Tags{"identifier": "test", "command": "pipeline"}
+		require.Len(t, store.Get("redis_command_duration", tags).Durations(), 1)
+		require.EqualValues(t, 1, store.Get("redis_command_errors", tags).LastValue())
+
+		spans, err := store.Spans()
+		require.NoError(t, err)
+		require.Len(t, spans, 1)
+		require.Equal(t, "redis.pipeline", spans[0].Name)
+		require.Contains(t, spans[0].Attributes, tracemodel.Attributes{
+			Key: "db.redis.num_cmd", Value: tracemodel.Value{Type: "INT64", Value: float64(2)},
+		})
+	})
+
+	t.Run("client", func(t *testing.T) {
+		store, err := memstats.New()
+		require.NoError(t, err)
+
+		client := redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1, DialTimeout: time.Second})
+		defer func() { _ = client.Close() }()
+		client.AddHook(redisutil.NewHook(store, "test"))
+
+		require.Error(t, client.Ping(context.Background()).Err())
+		tags := stats.Tags{"identifier": "test", "command": "ping"}
+		require.Len(t, store.Get("redis_command_duration", tags).Durations(), 1)
+		require.EqualValues(t, 1, store.Get("redis_command_errors", tags).LastValue())
+	})
+}
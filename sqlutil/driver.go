@@ -0,0 +1,221 @@
+package sqlutil
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+// NewInstrumentedConnector wraps a driver.Connector (e.g. the one returned by pq.NewConnector) so that every query
+// executed through it records timings, error counts, spans and slow query logs (see WithQueryName), e.g.
+//
+//	connector, err := pq.NewConnector(dsn)
+//	...
+//	db := sql.OpenDB(sqlutil.NewInstrumentedConnector(connector, conf, stats.Default, log, "jobsdb"))
+//
+// Queries taking longer than "Database.slowQueryThreshold" (default 5s, reloadable, 0 to disable) are logged.
+// The duration of a query is the time taken by the driver to return its result, i.e. not including the time taken
+// to iterate over the rows.
+func NewInstrumentedConnector(
+	connector driver.Connector,
+	conf *config.Config,
+	statsFactory stats.Stats,
+	log logger.Logger,
+	identifier string,
+) driver.Connector {
+	return &instrumentedConnector{
+		Connector: connector,
+		instrumentation: &instrumentation{
+			identifier:         identifier,
+			stats:              statsFactory,
+			tracer:             statsFactory.NewTracer(tracerName),
+			log:                log,
+			slowQueryThreshold: conf.GetReloadableDurationVar(5, time.Second, "Database.slowQueryThreshold"),
+		},
+	}
+}
+
+type instrumentedConnector struct {
+	driver.Connector
+	instrumentation *instrumentation
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, instrumentation: c.instrumentation}, nil
+}
+
+// instrumentedConn wraps a driver.Conn, implementing all the optional interfaces used by database/sql and falling back
+// to the behaviour database/sql would have if the wrapped connection doesn't implement them.
+type instrumentedConn struct {
+	driver.Conn
+	instrumentation *instrumentation
+}
+
+var (
+	_ driver.ConnPrepareContext = &instrumentedConn{}
+	_ driver.ConnBeginTx        = &instrumentedConn{}
+	_ driver.ExecerContext      = &instrumentedConn{}
+	_ driver.QueryerContext     = &instrumentedConn{}
+	_ driver.Pinger             = &instrumentedConn{}
+	_ driver.SessionResetter    = &instrumentedConn{}
+	_ driver.Validator          = &instrumentedConn{}
+	_ driver.NamedValueChecker  = &instrumentedConn{}
+)
+
+func (c *instrumentedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (stmt driver.Stmt, err error) {
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{Stmt: stmt, query: query, instrumentation: c.instrumentation}, nil
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bt, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bt.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+	return c.Conn.Begin() // nolint:staticcheck // fallback for drivers not implementing driver.ConnBeginTx
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var res driver.Result
+	err := c.instrumentation.observe(ctx, query, func(ctx context.Context) (err error) {
+		res, err = ec.ExecContext(ctx, query, args)
+		return err
+	})
+	return res, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var rows driver.Rows
+	err := c.instrumentation.observe(ctx, query, func(ctx context.Context) (err error) {
+		rows, err = qc.QueryContext(ctx, query, args)
+		return err
+	})
+	return rows, err
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.Conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *instrumentedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// instrumentedStmt wraps a driver.Stmt, falling back to the deprecated non context methods if the wrapped statement
+// doesn't implement their context counterparts.
+type instrumentedStmt struct {
+	driver.Stmt
+	query           string
+	instrumentation *instrumentation
+}
+
+var (
+	_ driver.StmtExecContext   = &instrumentedStmt{}
+	_ driver.StmtQueryContext  = &instrumentedStmt{}
+	_ driver.NamedValueChecker = &instrumentedStmt{}
+)
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	var res driver.Result
+	err := s.instrumentation.observe(ctx, s.query, func(ctx context.Context) (err error) {
+		if sec, ok := s.Stmt.(driver.StmtExecContext); ok {
+			res, err = sec.ExecContext(ctx, args)
+			return err
+		}
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return err
+		}
+		res, err = s.Stmt.Exec(values) // nolint:staticcheck // fallback for drivers not implementing driver.StmtExecContext
+		return err
+	})
+	return res, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := s.instrumentation.observe(ctx, s.query, func(ctx context.Context) (err error) {
+		if sqc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+			rows, err = sqc.QueryContext(ctx, args)
+			return err
+		}
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return err
+		}
+		rows, err = s.Stmt.Query(values) // nolint:staticcheck // fallback for drivers not implementing driver.StmtQueryContext
+		return err
+	})
+	return rows, err
+}
+
+func (s *instrumentedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValuesToValues(named []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(named))
+	for i, nv := range named {
+		if nv.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = nv.Value
+	}
+	return values, nil
+}
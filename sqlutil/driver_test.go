@@ -0,0 +1,240 @@
+package sqlutil_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+	"github.com/khulnasoft/go-kit/logger/mock_logger"
+	"github.com/khulnasoft/go-kit/sqlutil"
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+	"github.com/khulnasoft/go-kit/stats/testhelper/tracemodel"
+	"github.com/khulnasoft/go-kit/testhelper/docker/resource/postgres"
+)
+
+// dsnConnector is a driver.Connector opening connections using a driver and a DSN
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c *dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+// skippingDriver opens connections whose fast path always returns driver.ErrSkip, like lib/pq for statements with
+// arguments, thus executing statements through prepared statements
+type skippingDriver struct{}
+
+func (skippingDriver) Open(string) (driver.Conn, error) { return skippingConn{}, nil }
+
+type skippingConn struct{}
+
+func (skippingConn) Prepare(string) (driver.Stmt, error) { return skippingStmt{}, nil }
+func (skippingConn) Close() error                        { return nil }
+func (skippingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (skippingConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+type skippingStmt struct{}
+
+func (skippingStmt) Close() error                               { return nil }
+func (skippingStmt) NumInput() int                              { return -1 }
+func (skippingStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (skippingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sql state " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestInstrumentedConnector(t *testing.T) {
+	setup := func(t *testing.T, conf *config.Config, log logger.Logger) (*sql.DB, sqlmock.Sqlmock, *memstats.Store) {
+		t.Helper()
+		mockDB, mock, err := sqlmock.NewWithDSN(t.Name())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		store, err := memstats.New(memstats.WithTracing())
+		require.NoError(t, err)
+
+		db := sql.OpenDB(sqlutil.NewInstrumentedConnector(
+			&dsnConnector{dsn: t.Name(), driver: mockDB.Driver()}, conf, store, log, "test",
+		))
+		t.Cleanup(func() { _ = db.Close() })
+		db.SetMaxOpenConns(1)
+		return db, mock, store
+	}
+	spanAttribute := func(span tracemodel.Span, key string) any {
+		for _, attr := range span.Attributes {
+			if attr.Key == key {
+				return attr.Value.Value
+			}
+		}
+		return nil
+	}
+
+	t.Run("queries", func(t *testing.T) {
+		db, mock, store := setup(t, config.New(), logger.NOP)
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT").WillReturnError(sqlStateError("23505"))
+		mock.ExpectExec("DELETE").WillReturnError(errors.New("some error"))
+
+		ctx := sqlutil.WithQueryName(context.Background(), "get_job")
+		var id int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT id FROM jobs WHERE name = 'it''s' AND id > 10 AND col_1 = $1", "a").Scan(&id))
+		_, err := db.ExecContext(sqlutil.WithQueryName(context.Background(), "update_job"), "UPDATE jobs SET state = $1", "done")
+		require.NoError(t, err)
+		_, err = db.ExecContext(sqlutil.WithQueryName(context.Background(), "insert_job"), "INSERT INTO jobs VALUES (1)")
+		require.Error(t, err)
+		_, err = db.ExecContext(context.Background(), "DELETE FROM jobs")
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		for _, query := range []string{"get_job", "update_job", "insert_job", "unnamed"} {
+			require.Lenf(t, store.Get("sql_query_duration", stats.Tags{"identifier": "test", "query": query}).Durations(), 1, query)
+		}
+		require.Nil(t, store.Get("sql_query_errors", stats.Tags{"identifier": "test", "query": "get_job", "class": "other"}))
+		require.EqualValues(t, 1, store.Get("sql_query_errors", stats.Tags{
+			"identifier": "test", "query": "insert_job", "class": "integrity_constraint_violation",
+		}).LastValue())
+		require.EqualValues(t, 1, store.Get("sql_query_errors", stats.Tags{
+			"identifier": "test", "query": "unnamed", "class": "other",
+		}).LastValue())
+
+		spans, err := store.Spans()
+		require.NoError(t, err)
+		require.Len(t, spans, 4)
+		require.Equal(t, "sql.get_job", spans[0].Name)
+		require.Equal(t, int(stats.SpanKindClient), spans[0].SpanKind)
+		require.Equal(t, "SELECT id FROM jobs WHERE name = ? AND id > ? AND col_1 = $1", spanAttribute(spans[0], "db.statement"))
+		require.Equal(t, "SELECT", spanAttribute(spans[0], "db.operation"))
+		require.Equal(t, "sql.insert_job", spans[2].Name)
+		require.Equal(t, "INSERT INTO jobs VALUES (?)", spanAttribute(spans[2], "db.statement"))
+		require.Equal(t, "Error", spans[2].Status.Code)
+	})
+
+	t.Run("skipped fast path", func(t *testing.T) {
+		store, err := memstats.New(memstats.WithTracing())
+		require.NoError(t, err)
+		db := sql.OpenDB(sqlutil.NewInstrumentedConnector(
+			&dsnConnector{driver: skippingDriver{}}, config.New(), store, logger.NOP, "test",
+		))
+		defer func() { _ = db.Close() }()
+
+		_, err = db.ExecContext(sqlutil.WithQueryName(context.Background(), "skipped"), "UPDATE jobs SET state = $1", "done")
+		require.NoError(t, err)
+
+		require.Len(t, store.Get("sql_query_duration", stats.Tags{"identifier": "test", "query": "skipped"}).Durations(), 1)
+		spans, err := store.Spans()
+		require.NoError(t, err)
+		require.Len(t, spans, 1, "skipped calls have no span")
+		require.Equal(t, "sql.skipped", spans[0].Name)
+	})
+
+	t.Run("prepared statements", func(t *testing.T) {
+		db, mock, store := setup(t, config.New(), logger.NOP)
+		mock.ExpectPrepare("SELECT").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		ctx := sqlutil.WithQueryName(context.Background(), "prepared")
+		stmt, err := db.PrepareContext(ctx, "SELECT id FROM jobs WHERE id = $1")
+		require.NoError(t, err)
+		defer func() { _ = stmt.Close() }()
+		var id int
+		require.NoError(t, stmt.QueryRowContext(ctx, 1).Scan(&id))
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		require.Len(t, store.Get("sql_query_duration", stats.Tags{"identifier": "test", "query": "prepared"}).Durations(), 1)
+	})
+
+	t.Run("slow queries", func(t *testing.T) {
+		conf := config.New()
+		conf.Set("Database.slowQueryThreshold", "10ms")
+		ctrl := gomock.NewController(t)
+		log := mock_logger.NewMockLogger(ctrl)
+		log.EXPECT().Warnw("slow query",
+			"identifier", "test",
+			"query", "slow",
+			"duration", gomock.Any(),
+			"threshold", 10*time.Millisecond,
+			"statement", "SELECT pg_sleep(?)",
+		).Times(1)
+
+		db, mock, _ := setup(t, conf, log)
+		mock.ExpectExec("SELECT").WillDelayFor(20 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := db.ExecContext(sqlutil.WithQueryName(context.Background(), "slow"), "SELECT pg_sleep(0.02)")
+		require.NoError(t, err)
+
+		conf.Set("Database.slowQueryThreshold", "0s") // disabled
+		_, err = db.ExecContext(sqlutil.WithQueryName(context.Background(), "fast"), "SELECT 1")
+		require.NoError(t, err)
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		db, mock, store := setup(t, config.New(), logger.NOP)
+		mock.ExpectExec("SELECT").WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx, cancel := context.WithTimeout(sqlutil.WithQueryName(context.Background(), "timeout"), 10*time.Millisecond)
+		defer cancel()
+		_, err := db.ExecContext(ctx, "SELECT 1")
+		require.Error(t, err)
+
+		require.EqualValues(t, 1, store.Get("sql_query_errors", stats.Tags{
+			"identifier": "test", "query": "timeout", "class": "timeout",
+		}).LastValue())
+	})
+}
+
+func TestInstrumentedConnectorPostgres(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	postgresContainer, err := postgres.Setup(pool, t)
+	require.NoError(t, err)
+
+	connector, err := pq.NewConnector(postgresContainer.DBDsn)
+	require.NoError(t, err)
+	store, err := memstats.New(memstats.WithTracing())
+	require.NoError(t, err)
+	db := sql.OpenDB(sqlutil.NewInstrumentedConnector(connector, config.New(), store, logger.NOP, "postgres"))
+	defer func() { _ = db.Close() }()
+
+	ctx := sqlutil.WithQueryName(context.Background(), "create")
+	_, err = db.ExecContext(ctx, "CREATE TABLE jobs (id INT PRIMARY KEY)")
+	require.NoError(t, err)
+
+	ctx = sqlutil.WithQueryName(context.Background(), "insert")
+	_, err = db.ExecContext(ctx, "INSERT INTO jobs VALUES ($1)", 1)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO jobs VALUES ($1)", 1)
+	require.Error(t, err)
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, tx.QueryRowContext(sqlutil.WithQueryName(context.Background(), "count"), "SELECT COUNT(*) FROM jobs").Scan(&count))
+	require.NoError(t, tx.Commit())
+	require.Equal(t, 1, count)
+
+	require.Len(t, store.Get("sql_query_duration", stats.Tags{"identifier": "postgres", "query": "insert"}).Durations(), 2)
+	require.Len(t, store.Get("sql_query_duration", stats.Tags{"identifier": "postgres", "query": "count"}).Durations(), 1)
+	require.EqualValues(t, 1, store.Get("sql_query_errors", stats.Tags{
+		"identifier": "postgres", "query": "insert", "class": "integrity_constraint_violation",
+	}).LastValue())
+}
@@ -0,0 +1,285 @@
+package sqlutil
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	tracerName = "sqlutil"
+
+	// unnamedQuery is the query name used when none is provided via WithQueryName
+	unnamedQuery = "unnamed"
+)
+
+type queryNameKey struct{}
+
+// WithQueryName returns a context carrying the name used to tag the measurements, spans and logs of the queries
+// executed with it by an instrumented connector (see NewInstrumentedConnector), e.g.
+//
+//	rows, err := db.QueryContext(sqlutil.WithQueryName(ctx, "get_jobs"), "SELECT ...")
+//
+// Names should have a low cardinality, since they are used as tags.
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+func queryNameFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok && name != "" {
+		return name
+	}
+	return unnamedQuery
+}
+
+// instrumentation records the metrics, spans and slow query logs of the queries executed by an instrumented connector
+type instrumentation struct {
+	identifier         string
+	stats              stats.Stats
+	tracer             stats.Tracer
+	log                logger.Logger
+	slowQueryThreshold config.ValueLoader[time.Duration]
+}
+
+// observe runs fn, which executes the given SQL statement, recording:
+//   - its duration with the "sql_query_duration" timer;
+//   - its errors with the "sql_query_errors" counter, tagged by error class (see errorClass);
+//   - a client span with the sanitized statement;
+//   - a warning log if it took longer than the slow query threshold.
+//
+// driver.ErrSkip is not recorded, since database/sql falls back to another method to execute the statement.
+// The span is thus started once the statement is executed, with the time it started at.
+func (i *instrumentation) observe(ctx context.Context, statement string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	if errors.Is(err, driver.ErrSkip) {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	queryName := queryNameFromContext(ctx)
+	sanitized := sanitizeSQL(statement)
+	spanCtx, span := i.tracer.Start(ctx, "sql."+queryName, stats.SpanKindClient, stats.SpanWithTimestamp(start),
+		stats.SpanWithTags(stats.Tags{
+			"db.statement": sanitized,
+			"db.operation": sqlOperation(sanitized),
+			"identifier":   i.identifier,
+			"query":        queryName,
+		}),
+	)
+	defer span.End()
+
+	tags := stats.Tags{"identifier": i.identifier, "query": queryName}
+	i.stats.NewTaggedStat("sql_query_duration", stats.TimerType, tags).SendTimingCtx(spanCtx, elapsed)
+	if err != nil {
+		class := errorClass(err)
+		if ctxErr := ctx.Err(); ctxErr != nil { // drivers usually return their own errors when a query is canceled
+			class = errorClass(ctxErr)
+		}
+		i.stats.NewTaggedStat("sql_query_errors", stats.CountType, stats.Tags{
+			"identifier": i.identifier, "query": queryName, "class": class,
+		}).Increment()
+		span.RecordError(err, stats.SpanWithTags(stats.Tags{"class": class}))
+		span.SetStatus(stats.SpanStatusError, err.Error())
+	}
+
+	if threshold := i.slowQueryThreshold.Load(); threshold > 0 && elapsed >= threshold {
+		i.log.Warnw("slow query",
+			"identifier", i.identifier,
+			"query", queryName,
+			"duration", elapsed,
+			"threshold", threshold,
+			"statement", sanitized,
+		)
+	}
+	return err
+}
+
+// sqlStateClasses are the names of the most common SQLSTATE classes,
+// see https://www.postgresql.org/docs/current/errcodes-appendix.html
+var sqlStateClasses = map[string]string{
+	"08": "connection_exception",
+	"22": "data_exception",
+	"23": "integrity_constraint_violation",
+	"25": "invalid_transaction_state",
+	"28": "invalid_authorization",
+	"40": "transaction_rollback",
+	"42": "syntax_error_or_access_rule_violation",
+	"53": "insufficient_resources",
+	"54": "program_limit_exceeded",
+	"55": "object_not_in_prerequisite_state",
+	"57": "operator_intervention",
+	"58": "system_error",
+	"XX": "internal_error",
+}
+
+// errorClass returns a low cardinality class for the given error:
+//   - "canceled" and "timeout" for context errors;
+//   - "bad_connection" for driver.ErrBadConn and "network" for other network errors;
+//   - the SQLSTATE class for errors exposing a SQLSTATE (e.g. *pq.Error), e.g. "integrity_constraint_violation";
+//   - "other" otherwise.
+func errorClass(err error) string {
+	var (
+		sqlStateErr interface{ SQLState() string }
+		netErr      net.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, driver.ErrBadConn):
+		return "bad_connection"
+	case errors.As(err, &sqlStateErr) && len(sqlStateErr.SQLState()) == 5:
+		class := sqlStateErr.SQLState()[:2]
+		if name, ok := sqlStateClasses[class]; ok {
+			return name
+		}
+		return "sqlstate_" + class
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+// sanitizeSQL replaces the string and numeric literals of a SQL statement with "?", drops comments and collapses
+// whitespaces, so that it can be safely logged and used as a span attribute. Placeholders (e.g. $1) and identifiers,
+// quoted or not, are kept. Literals include PostgreSQL escape strings (e.g. E'it\'s') and dollar-quoted strings
+// (e.g. $$it's$$ or $body$it's$body$).
+func sanitizeSQL(statement string) string {
+	var (
+		runes     = []rune(statement)
+		res       = make([]rune, 0, len(runes))
+		prev      rune
+		lastSpace = true
+	)
+	space := func() {
+		if !lastSpace {
+			res = append(res, ' ')
+		}
+		prev, lastSpace = ' ', true
+	}
+	literal := func() {
+		res = append(res, '?')
+		prev, lastSpace = '?', false
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-': // line comment
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space()
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*': // block comment, which can be nested in PostgreSQL
+			depth := 1
+			for i += 2; i < len(runes) && depth > 0; i++ {
+				if runes[i] == '/' && i+1 < len(runes) && runes[i+1] == '*' {
+					depth, i = depth+1, i+1
+				} else if runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/' {
+					depth, i = depth-1, i+1
+				}
+			}
+			i--
+			space()
+		case r == '"': // quoted identifier, where quotes are escaped by doubling them
+			j := skipQuoted(runes, i, '"', false)
+			res = append(res, runes[i:j+1]...)
+			i, prev, lastSpace = j, '"', false
+		case r == '\'': // string literal, where quotes are escaped by doubling them, or by a backslash in escape strings
+			// escape strings are prefixed by E, which is dropped along with the literal
+			escapes := (prev == 'E' || prev == 'e') && (len(res) == 1 || !isIdentifierRune(res[len(res)-2]))
+			if escapes {
+				res = res[:len(res)-1]
+			}
+			i = skipQuoted(runes, i, '\'', escapes)
+			literal()
+		case r == '$' && !isIdentifierRune(prev): // dollar-quoted string literal, unless a placeholder
+			if tag, ok := dollarQuoteTag(runes, i); ok {
+				i = skipDollarQuoted(runes, i+len(tag), tag)
+				literal()
+				continue
+			}
+			res = append(res, r)
+			prev, lastSpace = r, false
+		case unicode.IsDigit(r) && !isIdentifierRune(prev):
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			literal()
+		case unicode.IsSpace(r):
+			space()
+		default:
+			res = append(res, r)
+			prev, lastSpace = r, false
+		}
+	}
+	return strings.TrimSpace(string(res))
+}
+
+// skipQuoted returns the index of the quote closing the quoted string starting at index start, or the index of the
+// last rune if the string isn't closed
+func skipQuoted(runes []rune, start int, quote rune, backslashEscapes bool) int {
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case backslashEscapes && runes[i] == '\\':
+			i++
+		case runes[i] == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(runes) - 1
+}
+
+// dollarQuoteTag returns the delimiter of the dollar-quoted string starting at index start, e.g. "$$" or "$body$",
+// and false if there is none, e.g. for placeholders like $1
+func dollarQuoteTag(runes []rune, start int) ([]rune, bool) {
+	for i := start + 1; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '$':
+			return runes[start : i+1], true
+		case r == '_' || unicode.IsLetter(r) || (unicode.IsDigit(r) && i > start+1):
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// skipDollarQuoted returns the index of the last rune of the delimiter closing the dollar-quoted string whose body
+// starts at index start, or the index of the last rune if the string isn't closed
+func skipDollarQuoted(runes []rune, start int, tag []rune) int {
+	for i := start; i+len(tag) <= len(runes); i++ {
+		if slices.Equal(runes[i:i+len(tag)], tag) {
+			return i + len(tag) - 1
+		}
+	}
+	return len(runes) - 1
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || r == '"' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// sqlOperation returns the first keyword of a statement, e.g. "SELECT"
+func sqlOperation(statement string) string {
+	operation, _, _ := strings.Cut(statement, " ")
+	return strings.ToUpper(strings.TrimLeft(operation, "("))
+}
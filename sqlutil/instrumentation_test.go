@@ -0,0 +1,68 @@
+package sqlutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	for _, tc := range []struct {
+		name, statement, expected string
+	}{
+		{
+			name:      "literals",
+			statement: "SELECT id FROM jobs WHERE name = 'it''s' AND id > 10 AND col_1 = $1",
+			expected:  "SELECT id FROM jobs WHERE name = ? AND id > ? AND col_1 = $1",
+		},
+		{
+			name:      "whitespaces",
+			statement: "\n\tSELECT  *\n\tFROM jobs\n",
+			expected:  "SELECT * FROM jobs",
+		},
+		{
+			name:      "line comments",
+			statement: "SELECT id -- don't log 'secret'\nFROM jobs WHERE name = 'secret'",
+			expected:  "SELECT id FROM jobs WHERE name = ?",
+		},
+		{
+			name:      "block comments",
+			statement: "SELECT /* it's /* nested */ 'secret' */ id FROM jobs WHERE name = 'secret'",
+			expected:  "SELECT id FROM jobs WHERE name = ?",
+		},
+		{
+			name:      "unterminated comment",
+			statement: "SELECT id /* 'secret'",
+			expected:  "SELECT id",
+		},
+		{
+			name:      "dollar-quoted strings",
+			statement: "SELECT $$it's$$, $body$ 'secret' $$ $body$, 'secret' FROM jobs WHERE id = $1",
+			expected:  "SELECT ?, ?, ? FROM jobs WHERE id = $1",
+		},
+		{
+			name:      "unterminated dollar-quoted string",
+			statement: "SELECT $tag$ 'secret'",
+			expected:  "SELECT ?",
+		},
+		{
+			name:      "escape strings",
+			statement: `SELECT E'it\'s', e'\\', 'secret' FROM jobs WHERE type = 'e'`,
+			expected:  "SELECT ?, ?, ? FROM jobs WHERE type = ?",
+		},
+		{
+			name:      "quoted identifiers",
+			statement: `SELECT "it's", "a""b" FROM jobs WHERE "name" = 'secret' AND "col1" = 1`,
+			expected:  `SELECT "it's", "a""b" FROM jobs WHERE "name" = ? AND "col1" = ?`,
+		},
+		{
+			name:      "identifiers",
+			statement: "SELECT col_1, a$b, price FROM jobs",
+			expected:  "SELECT col_1, a$b, price FROM jobs",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, sanitizeSQL(tc.statement))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// startTime approximates the start time of the process, see buildInfoCollector
+var startTime = time.Now()
+
+// buildInfoCollector is a Collector reporting static information about the running service:
+//   - build_info, always 1, tagged with the service name and version, the Go version and the VCS revision
+//     (if the binary was built with VCS stamping, see debug.ReadBuildInfo). Empty tags are omitted;
+//   - service_start_time_seconds, the start time of the service as a unix timestamp;
+//   - service_uptime_seconds, the number of seconds elapsed since the service started.
+//
+// It is registered by default, see WithoutBuildInfo.
+type buildInfoCollector struct {
+	tags      Tags
+	startTime time.Time
+	now       func() time.Time
+}
+
+func newBuildInfoCollector(serviceName, serviceVersion string) *buildInfoCollector {
+	tags := Tags{
+		"serviceName":    serviceName,
+		"serviceVersion": serviceVersion,
+		"goVersion":      runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				tags["vcsRevision"] = setting.Value
+			case "vcs.modified":
+				tags["vcsModified"] = setting.Value
+			}
+		}
+	}
+	for k, v := range tags {
+		if v == "" {
+			delete(tags, k)
+		}
+	}
+	return &buildInfoCollector{tags: tags, startTime: startTime, now: time.Now}
+}
+
+func (c *buildInfoCollector) Collect(gaugeFunc gaugeTagsFunc) {
+	gaugeFunc("build_info", c.tags, 1)
+	gaugeFunc("service_start_time_seconds", nil, uint64(c.startTime.Unix()))
+	gaugeFunc("service_uptime_seconds", nil, uint64(c.now().Sub(c.startTime).Seconds()))
+}
+
+func (c *buildInfoCollector) Zero(gaugeFunc gaugeTagsFunc) {
+	gaugeFunc("build_info", c.tags, 0)
+	gaugeFunc("service_start_time_seconds", nil, 0)
+	gaugeFunc("service_uptime_seconds", nil, 0)
+}
+
+func (*buildInfoCollector) ID() string {
+	return "build_info"
+}
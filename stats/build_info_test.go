@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promClient "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+	"github.com/khulnasoft/go-kit/stats/metric"
+)
+
+func TestBuildInfoCollector(t *testing.T) {
+	type gauge struct {
+		tags  Tags
+		value uint64
+	}
+	collect := func(f func(gaugeTagsFunc)) map[string]gauge {
+		gauges := make(map[string]gauge)
+		f(func(key string, tags Tags, val uint64) { gauges[key] = gauge{tags: tags, value: val} })
+		return gauges
+	}
+
+	c := newBuildInfoCollector("my-service", "v1.2.3")
+	c.startTime = time.Unix(1700000000, 0)
+	c.now = func() time.Time { return c.startTime.Add(90 * time.Second) }
+
+	gauges := collect(c.Collect)
+	require.Len(t, gauges, 3)
+	require.EqualValues(t, 1, gauges["build_info"].value)
+	require.Equal(t, "my-service", gauges["build_info"].tags["serviceName"])
+	require.Equal(t, "v1.2.3", gauges["build_info"].tags["serviceVersion"])
+	require.Equal(t, runtime.Version(), gauges["build_info"].tags["goVersion"])
+	require.EqualValues(t, 1700000000, gauges["service_start_time_seconds"].value)
+	require.EqualValues(t, 90, gauges["service_uptime_seconds"].value)
+
+	for name, g := range collect(c.Zero) {
+		require.Zerof(t, g.value, name)
+	}
+
+	t.Run("empty tags are omitted", func(t *testing.T) {
+		c := newBuildInfoCollector("", "")
+		require.NotContains(t, c.tags, "serviceName")
+		require.NotContains(t, c.tags, "serviceVersion")
+		require.Contains(t, c.tags, "goVersion")
+	})
+}
+
+func TestOTelBuildInfo(t *testing.T) {
+	start := func(t *testing.T, opts ...Option) *prometheus.Registry {
+		t.Helper()
+		c := config.New()
+		c.Set("OpenTelemetry.enabled", true)
+		c.Set("OpenTelemetry.metrics.prometheus.enabled", true)
+		c.Set("RuntimeStats.enabled", false)
+		r := prometheus.NewRegistry()
+		opts = append(opts, WithServiceName(t.Name()), WithServiceVersion("v1.2.3"), WithPrometheusRegistry(r, r))
+		s := NewStats(c, logger.NewFactory(c), metric.NewManager(), opts...)
+		require.NoError(t, s.Start(context.Background(), DefaultGoRoutineFactory))
+		t.Cleanup(s.Stop)
+		return r
+	}
+	gather := func(t *testing.T, r *prometheus.Registry) map[string]*promClient.Metric {
+		t.Helper()
+		mfs, err := r.Gather()
+		require.NoError(t, err)
+		metrics := make(map[string]*promClient.Metric)
+		for _, mf := range mfs {
+			metrics[mf.GetName()] = mf.GetMetric()[0]
+		}
+		return metrics
+	}
+
+	t.Run("enabled by default", func(t *testing.T) {
+		r := start(t)
+		require.Eventually(t, func() bool {
+			_, ok := gather(t, r)["build_info"]
+			return ok
+		}, 5*time.Second, 10*time.Millisecond)
+
+		metrics := gather(t, r)
+		require.Equal(t, 1.0, metrics["build_info"].GetGauge().GetValue())
+		require.Subset(t, metrics["build_info"].GetLabel(), []*promClient.LabelPair{
+			{Name: ptr("goVersion"), Value: ptr(runtime.Version())},
+			{Name: ptr("serviceName"), Value: ptr(t.Name())},
+			{Name: ptr("serviceVersion"), Value: ptr("v1.2.3")},
+		})
+		require.Equal(t, float64(startTime.Unix()), metrics["service_start_time_seconds"].GetGauge().GetValue())
+		require.Contains(t, metrics, "service_uptime_seconds")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		r := start(t, WithoutBuildInfo())
+		require.Never(t, func() bool {
+			_, ok := gather(t, r)["build_info"]
+			return ok
+		}, 100*time.Millisecond, 10*time.Millisecond)
+		require.NotContains(t, gather(t, r), "service_uptime_seconds")
+	})
+}
@@ -0,0 +1,71 @@
+// Command spantree pretty-prints the span trees written by the OpenTelemetry file and stdout trace exporters
+// (i.e. with OpenTelemetry.traces.exporter=file|stdout), e.g.
+//
+//	go run github.com/khulnasoft/go-kit/stats/cmd/spantree -attributes otel-traces.jsonl
+//
+// If no file is provided the spans are read from the standard input.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/khulnasoft/go-kit/stats/internal/otel/otlpjson"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "spantree:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("spantree", flag.ContinueOnError)
+	withAttributes := fs.Bool("attributes", false, "print the attributes and events of the spans")
+	traceID := fs.String("trace", "", "print only the trace with the given id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var spans []*otlpjson.Span
+	if fs.NArg() == 0 {
+		var err error
+		if spans, err = otlpjson.ReadSpans(stdin); err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+	}
+	for _, path := range fs.Args() {
+		fileSpans, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		spans = append(spans, fileSpans...)
+	}
+
+	if *traceID != "" {
+		filtered := spans[:0]
+		for _, s := range spans {
+			if s.TraceID == *traceID {
+				filtered = append(filtered, s)
+			}
+		}
+		spans = filtered
+	}
+	return otlpjson.PrintTrees(stdout, otlpjson.BuildTrees(spans), *withAttributes)
+}
+
+func readFile(path string) ([]*otlpjson.Span, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	spans, err := otlpjson.ReadSpans(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return spans, nil
+}
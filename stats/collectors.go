@@ -22,24 +22,42 @@ type aggregatedCollector struct {
 	PauseDur  time.Duration
 	gaugeFunc gaugeTagsFunc
 	mu        sync.Mutex
+	onAdd     func() // called by the next Add, see runOnceAdded
 }
 
 func (p *aggregatedCollector) Add(c Collector) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if p.c == nil {
 		p.c = make(map[string]Collector)
 	}
 
 	if _, ok := p.c[c.ID()]; ok {
+		p.mu.Unlock()
 		return fmt.Errorf("collector with ID %s already register", c.ID())
 	}
 
 	p.c[c.ID()] = c
+	onAdd := p.onAdd
+	p.onAdd = nil
+	p.mu.Unlock()
+	if onAdd != nil {
+		onAdd()
+	}
 	return nil
 }
 
+// runOnceAdded calls run right away if collectors were added already, or once the first one is added otherwise
+func (p *aggregatedCollector) runOnceAdded(run func()) {
+	p.mu.Lock()
+	if len(p.c) == 0 {
+		p.onAdd = run
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	run()
+}
+
 func (p *aggregatedCollector) Run(ctx context.Context) {
 	defer p.allZero()
 	p.allCollect()
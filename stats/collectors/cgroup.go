@@ -0,0 +1,128 @@
+package collectors
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	cgroupCPUStatsUniqName = "cgroup_cpu"
+
+	// cgroups v1 interface files, see https://www.kernel.org/doc/Documentation/cgroup-v1/cpuacct.txt
+	// and https://www.kernel.org/doc/Documentation/scheduler/sched-bwc.txt
+	cgroupV1CPUPrefix     = "/sys/fs/cgroup/cpu"
+	cgroupV1CPUAcctPrefix = "/sys/fs/cgroup/cpuacct"
+
+	// cgroups v2 interface files, see https://www.kernel.org/doc/html/latest/admin-guide/cgroup-v2.html#cpu-interface-files
+	cgroupV2Prefix = "/sys/fs/cgroup"
+)
+
+// CgroupCPUStats collects the CPU usage and throttling statistics of the cgroup (v1 or v2) the process belongs to.
+//
+// Times are reported in microseconds:
+//   - cgroup_cpu_usage_microseconds_total, cgroup_cpu_user_microseconds_total and cgroup_cpu_system_microseconds_total
+//     for the CPU time consumed by the cgroup;
+//   - cgroup_cpu_periods_total, cgroup_cpu_throttled_periods_total and cgroup_cpu_throttled_microseconds_total
+//     for the CFS bandwidth control;
+//   - cgroup_cpu_limit_millicores for the CPU limit, if any.
+//
+// No stats are collected if no cgroup information is available.
+type CgroupCPUStats struct {
+	basePath string
+}
+
+// NewCgroupCPUStats creates a new cgroup CPU collector reading cgroup files relatively to basePath.
+// An empty basePath reads them from the root filesystem.
+func NewCgroupCPUStats(basePath string) *CgroupCPUStats {
+	return &CgroupCPUStats{basePath: basePath}
+}
+
+func (s *CgroupCPUStats) Collect(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	for _, g := range s.read() {
+		gaugeFunc(g.key, g.tags, g.value)
+	}
+}
+
+func (s *CgroupCPUStats) Zero(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	for _, g := range s.read() {
+		gaugeFunc(g.key, g.tags, 0)
+	}
+}
+
+func (s *CgroupCPUStats) ID() string {
+	return cgroupCPUStatsUniqName
+}
+
+func (s *CgroupCPUStats) read() []gauge {
+	if gauges, ok := s.readV2(); ok {
+		return gauges
+	}
+	return s.readV1()
+}
+
+func (s *CgroupCPUStats) readV2() ([]gauge, bool) {
+	data, err := readCgroupFile(s.basePath, cgroupV2Prefix, "", "cpu.stat")
+	if err != nil {
+		return nil, false
+	}
+	cpuStat := parseFlatKeyed(data)
+	usage, ok := cpuStat["usage_usec"]
+	if !ok {
+		return nil, false
+	}
+
+	gauges := []gauge{
+		{key: "cgroup_cpu_usage_microseconds_total", value: usage},
+		{key: "cgroup_cpu_user_microseconds_total", value: cpuStat["user_usec"]},
+		{key: "cgroup_cpu_system_microseconds_total", value: cpuStat["system_usec"]},
+		{key: "cgroup_cpu_periods_total", value: cpuStat["nr_periods"]},
+		{key: "cgroup_cpu_throttled_periods_total", value: cpuStat["nr_throttled"]},
+		{key: "cgroup_cpu_throttled_microseconds_total", value: cpuStat["throttled_usec"]},
+	}
+
+	// cpu.max contains "$MAX $PERIOD", where $MAX is "max" if there is no limit
+	if data, err := readCgroupFile(s.basePath, cgroupV2Prefix, "", "cpu.max"); err == nil {
+		if fields := strings.Fields(data); len(fields) == 2 {
+			quota, quotaErr := strconv.ParseUint(fields[0], 10, 64)
+			period, periodErr := strconv.ParseUint(fields[1], 10, 64)
+			if quotaErr == nil && periodErr == nil && period > 0 {
+				gauges = append(gauges, gauge{key: "cgroup_cpu_limit_millicores", value: quota * 1000 / period})
+			}
+		}
+	}
+	return gauges, true
+}
+
+func (s *CgroupCPUStats) readV1() []gauge {
+	var gauges []gauge
+
+	// cpuacct.usage* files report nanoseconds
+	for key, fileName := range map[string]string{
+		"cgroup_cpu_usage_microseconds_total":  "cpuacct.usage",
+		"cgroup_cpu_user_microseconds_total":   "cpuacct.usage_user",
+		"cgroup_cpu_system_microseconds_total": "cpuacct.usage_sys",
+	} {
+		if n, err := readUint(s.basePath, cgroupV1CPUAcctPrefix, "cpuacct", fileName); err == nil {
+			gauges = append(gauges, gauge{key: key, value: n / 1000})
+		}
+	}
+
+	if data, err := readCgroupFile(s.basePath, cgroupV1CPUPrefix, "cpu", "cpu.stat"); err == nil {
+		cpuStat := parseFlatKeyed(data)
+		gauges = append(gauges,
+			gauge{key: "cgroup_cpu_periods_total", value: cpuStat["nr_periods"]},
+			gauge{key: "cgroup_cpu_throttled_periods_total", value: cpuStat["nr_throttled"]},
+			gauge{key: "cgroup_cpu_throttled_microseconds_total", value: cpuStat["throttled_time"] / 1000},
+		)
+	}
+
+	// cpu.cfs_quota_us is -1 if there is no limit
+	quota, quotaErr := readUint(s.basePath, cgroupV1CPUPrefix, "cpu", "cpu.cfs_quota_us")
+	period, periodErr := readUint(s.basePath, cgroupV1CPUPrefix, "cpu", "cpu.cfs_period_us")
+	if quotaErr == nil && periodErr == nil && period > 0 {
+		gauges = append(gauges, gauge{key: "cgroup_cpu_limit_millicores", value: quota * 1000 / period})
+	}
+	return gauges
+}
@@ -0,0 +1,85 @@
+package collectors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/collectors"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+func TestCgroupCPU(t *testing.T) {
+	collect := func(t *testing.T, basePath string) map[string]float64 {
+		t.Helper()
+		m, err := memstats.New()
+		require.NoError(t, err)
+		require.NoError(t, m.RegisterCollector(collectors.NewCgroupCPUStats(basePath)))
+
+		values := make(map[string]float64)
+		for _, metric := range m.GetAll() {
+			require.Empty(t, metric.Tags)
+			values[metric.Name] = metric.Value
+		}
+		return values
+	}
+
+	t.Run("cgroups v1 with limit", func(t *testing.T) {
+		require.Equal(t, map[string]float64{
+			"cgroup_cpu_usage_microseconds_total":     98765432,
+			"cgroup_cpu_user_microseconds_total":      80000000,
+			"cgroup_cpu_system_microseconds_total":    18765432,
+			"cgroup_cpu_periods_total":                1200,
+			"cgroup_cpu_throttled_periods_total":      35,
+			"cgroup_cpu_throttled_microseconds_total": 4500000,
+			"cgroup_cpu_limit_millicores":             1500,
+		}, collect(t, "testdata/cgroups_v1_cpu_limit"))
+	})
+
+	t.Run("cgroups v1 no limit with self path", func(t *testing.T) {
+		require.Equal(t, map[string]float64{
+			"cgroup_cpu_usage_microseconds_total":     5000000,
+			"cgroup_cpu_periods_total":                0,
+			"cgroup_cpu_throttled_periods_total":      0,
+			"cgroup_cpu_throttled_microseconds_total": 0,
+		}, collect(t, "testdata/cgroups_v1_cpu_no_limit_proc_self"))
+	})
+
+	t.Run("cgroups v2 with limit", func(t *testing.T) {
+		require.Equal(t, map[string]float64{
+			"cgroup_cpu_usage_microseconds_total":     7348291,
+			"cgroup_cpu_user_microseconds_total":      5123456,
+			"cgroup_cpu_system_microseconds_total":    2224835,
+			"cgroup_cpu_periods_total":                845,
+			"cgroup_cpu_throttled_periods_total":      12,
+			"cgroup_cpu_throttled_microseconds_total": 345678,
+			"cgroup_cpu_limit_millicores":             2000,
+		}, collect(t, "testdata/cgroups_v2_cpu_limit"))
+	})
+
+	t.Run("cgroups v2 no limit with self path", func(t *testing.T) {
+		require.Equal(t, map[string]float64{
+			"cgroup_cpu_usage_microseconds_total":     1000,
+			"cgroup_cpu_user_microseconds_total":      600,
+			"cgroup_cpu_system_microseconds_total":    400,
+			"cgroup_cpu_periods_total":                0,
+			"cgroup_cpu_throttled_periods_total":      0,
+			"cgroup_cpu_throttled_microseconds_total": 0,
+		}, collect(t, "testdata/cgroups_v2_cpu_no_limit_proc_self"))
+	})
+
+	t.Run("no cgroups info", func(t *testing.T) {
+		require.Empty(t, collect(t, "testdata/invalid_path"))
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		c := collectors.NewCgroupCPUStats("testdata/cgroups_v2_cpu_limit")
+		var zeroed int
+		c.Zero(func(key string, tags stats.Tags, val uint64) {
+			require.Zero(t, val, key)
+			zeroed++
+		})
+		require.Equal(t, 7, zeroed)
+	})
+}
@@ -0,0 +1,96 @@
+package collectors
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	pressureStatsUniqName = "pressure"
+
+	// system wide pressure files, see https://docs.kernel.org/accounting/psi.html
+	procPressurePrefix = "/proc/pressure"
+)
+
+// pressureResources are the resources for which pressure stall information is collected
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// PressureStats collects the pressure stall information (PSI) of the cgroup v2 the process belongs to, falling back
+// to the system wide one if not available (e.g. with cgroups v1).
+//
+// The total stall time is reported in microseconds as pressure_stall_microseconds_total, tagged with the resource
+// (cpu, memory or io) and the kind of stall: "some" if at least one task was stalled, "full" if all non-idle tasks
+// were stalled at the same time.
+type PressureStats struct {
+	basePath string
+}
+
+// NewPressureStats creates a new PSI collector reading pressure files relatively to basePath.
+// An empty basePath reads them from the root filesystem.
+func NewPressureStats(basePath string) *PressureStats {
+	return &PressureStats{basePath: basePath}
+}
+
+func (s *PressureStats) Collect(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	for _, g := range s.read() {
+		gaugeFunc(g.key, g.tags, g.value)
+	}
+}
+
+func (s *PressureStats) Zero(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	for _, g := range s.read() {
+		gaugeFunc(g.key, g.tags, 0)
+	}
+}
+
+func (s *PressureStats) ID() string {
+	return pressureStatsUniqName
+}
+
+func (s *PressureStats) read() []gauge {
+	var gauges []gauge
+	for _, resource := range pressureResources {
+		data, err := readCgroupFile(s.basePath, cgroupV2Prefix, "", resource+".pressure")
+		if err != nil {
+			raw, err := os.ReadFile(path.Join(s.basePath, procPressurePrefix, resource))
+			if err != nil {
+				continue
+			}
+			data = string(raw)
+		}
+		for kind, total := range parsePressure(data) {
+			gauges = append(gauges, gauge{
+				key:   "pressure_stall_microseconds_total",
+				tags:  stats.Tags{"resource": resource, "kind": kind},
+				value: total,
+			})
+		}
+	}
+	return gauges
+}
+
+// parsePressure returns the total stall time by kind of a pressure file, i.e. lines in the form of
+// "some avg10=0.00 avg60=0.00 avg300=0.00 total=0"
+func parsePressure(data string) map[string]uint64 {
+	totals := make(map[string]uint64)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			value, ok := strings.CutPrefix(field, "total=")
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+				totals[fields[0]] = n
+			}
+		}
+	}
+	return totals
+}
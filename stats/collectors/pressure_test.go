@@ -0,0 +1,46 @@
+package collectors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/collectors"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+func TestPressure(t *testing.T) {
+	collect := func(t *testing.T, basePath string) []memstats.Metric {
+		t.Helper()
+		m, err := memstats.New()
+		require.NoError(t, err)
+		require.NoError(t, m.RegisterCollector(collectors.NewPressureStats(basePath)))
+		return m.GetAll()
+	}
+	expected := func(totals ...float64) []memstats.Metric {
+		var metrics []memstats.Metric
+		for i, resource := range []string{"cpu", "memory", "io"} {
+			for j, kind := range []string{"some", "full"} {
+				metrics = append(metrics, memstats.Metric{
+					Name:  "pressure_stall_microseconds_total",
+					Tags:  stats.Tags{"resource": resource, "kind": kind},
+					Value: totals[2*i+j],
+				})
+			}
+		}
+		return metrics
+	}
+
+	t.Run("cgroups v2", func(t *testing.T) {
+		require.ElementsMatch(t, expected(111, 22, 333, 44, 555, 66), collect(t, "testdata/cgroups_v2_cpu_limit"))
+	})
+
+	t.Run("system wide", func(t *testing.T) {
+		require.ElementsMatch(t, expected(123456, 0, 2345, 1234, 34567, 23456), collect(t, "testdata/cgroups_v1_cpu_limit"))
+	})
+
+	t.Run("no pressure info", func(t *testing.T) {
+		require.Empty(t, collect(t, "testdata/invalid_path"))
+	})
+}
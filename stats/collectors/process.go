@@ -0,0 +1,84 @@
+package collectors
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+const (
+	processStatsUniqName = "process"
+
+	procSelfPrefix = "/proc/self"
+)
+
+// ProcessStats collects statistics about the current process from procfs:
+//   - process_open_fds and process_max_fds for the number of open file descriptors and their limit;
+//   - process_threads for the number of OS threads;
+//   - process_resident_memory_bytes for the resident set size (RSS).
+//
+// Stats that cannot be read (e.g. on systems without procfs) are not collected.
+type ProcessStats struct {
+	basePath string
+}
+
+// NewProcessStats creates a new process collector reading procfs files relatively to basePath.
+// An empty basePath reads them from the root filesystem.
+func NewProcessStats(basePath string) *ProcessStats {
+	return &ProcessStats{basePath: basePath}
+}
+
+func (s *ProcessStats) Collect(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	for _, g := range s.read() {
+		gaugeFunc(g.key, g.tags, g.value)
+	}
+}
+
+func (s *ProcessStats) Zero(gaugeFunc func(key string, tag stats.Tags, val uint64)) {
+	for _, g := range s.read() {
+		gaugeFunc(g.key, g.tags, 0)
+	}
+}
+
+func (s *ProcessStats) ID() string {
+	return processStatsUniqName
+}
+
+func (s *ProcessStats) read() []gauge {
+	var gauges []gauge
+	procSelf := path.Join(s.basePath, procSelfPrefix)
+
+	if fds, err := os.ReadDir(path.Join(procSelf, "fd")); err == nil {
+		gauges = append(gauges, gauge{key: "process_open_fds", value: uint64(len(fds))})
+	}
+
+	// e.g. "Max open files            1024                 1048576              files"
+	if data, err := os.ReadFile(path.Join(procSelf, "limits")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			value, ok := strings.CutPrefix(line, "Max open files")
+			if !ok {
+				continue
+			}
+			if fields := strings.Fields(value); len(fields) > 0 {
+				if n, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+					gauges = append(gauges, gauge{key: "process_max_fds", value: n})
+				}
+			}
+		}
+	}
+
+	// e.g. "Threads:	12" and "VmRSS:	   12345 kB"
+	if data, err := os.ReadFile(path.Join(procSelf, "status")); err == nil {
+		status := parseFlatKeyed(string(data))
+		if threads, ok := status["Threads"]; ok {
+			gauges = append(gauges, gauge{key: "process_threads", value: threads})
+		}
+		if rss, ok := status["VmRSS"]; ok {
+			gauges = append(gauges, gauge{key: "process_resident_memory_bytes", value: rss * 1024})
+		}
+	}
+	return gauges
+}
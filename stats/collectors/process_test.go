@@ -0,0 +1,40 @@
+package collectors_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/collectors"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+func TestProcess(t *testing.T) {
+	t.Run("fixture", func(t *testing.T) {
+		m, err := memstats.New()
+		require.NoError(t, err)
+		require.NoError(t, m.RegisterCollector(collectors.NewProcessStats("testdata/proc_self")))
+
+		require.ElementsMatch(t, []memstats.Metric{
+			{Name: "process_open_fds", Value: 5},
+			{Name: "process_max_fds", Value: 1048576},
+			{Name: "process_threads", Value: 14},
+			{Name: "process_resident_memory_bytes", Value: 52428 * 1024},
+		}, m.GetAll())
+	})
+
+	t.Run("current process", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("procfs is only available on linux")
+		}
+		m, err := memstats.New()
+		require.NoError(t, err)
+		require.NoError(t, m.RegisterCollector(collectors.NewProcessStats("")))
+
+		for _, name := range []string{"process_open_fds", "process_max_fds", "process_threads", "process_resident_memory_bytes"} {
+			require.Greater(t, m.Get(name, stats.Tags{}).LastValue(), 0.0, name)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+// readCgroupFile reads the content of a cgroup file, looking for it first in sysfsPrefix and then in the sub path of
+// sysfsPrefix found for the given controller in /proc/self/cgroup.
+// For cgroups v2 the controller is empty, i.e. the sub path is read from the "0::" line.
+func readCgroupFile(basePath, sysfsPrefix, controller, fileName string) (string, error) {
+	sysfsPrefix = path.Join(basePath, sysfsPrefix)
+	data, err := os.ReadFile(path.Join(sysfsPrefix, fileName))
+	if err == nil {
+		return string(data), nil
+	}
+	cgroupData, err := os.ReadFile(path.Join(basePath, "/proc/self/cgroup"))
+	if err != nil {
+		return "", err
+	}
+	subPath, ok := cgroupSubPath(string(cgroupData), controller)
+	if !ok {
+		return "", fmt.Errorf("cannot find cgroup path for controller %q", controller)
+	}
+	data, err = os.ReadFile(path.Join(sysfsPrefix, subPath, fileName))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// cgroupSubPath returns the path of the cgroup the process belongs to for the given controller,
+// according to the content of /proc/self/cgroup, i.e. lines in the form of "hierarchy-ID:controller-list:cgroup-path"
+func cgroupSubPath(data, controller string) (string, bool) {
+	for _, line := range strings.Split(data, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, c := range strings.Split(parts[1], ",") {
+			if c == controller {
+				return parts[2], true
+			}
+		}
+	}
+	return "", false
+}
+
+// readUint reads a file containing a single unsigned integer
+func readUint(basePath, sysfsPrefix, controller, fileName string) (uint64, error) {
+	data, err := readCgroupFile(basePath, sysfsPrefix, controller, fileName)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(data), 10, 64)
+}
+
+// parseFlatKeyed parses the content of a "flat keyed" file, i.e. lines in the form of "key value".
+// Values that are not unsigned integers are ignored.
+func parseFlatKeyed(data string) map[string]uint64 {
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		n, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = n
+	}
+	return values
+}
+
+// gauge is a single value reported by a collector
+type gauge struct {
+	key   string
+	tags  stats.Tags
+	value uint64
+}
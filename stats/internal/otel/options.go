@@ -8,6 +8,8 @@ import (
 	"go.opentelemetry.io/otel/sdk/instrumentation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/khulnasoft/go-kit/stats/internal/otel/otlpjson"
 )
 
 type (
@@ -75,6 +77,14 @@ func WithTracingSamplingRate(rate float64) TracerProviderOption {
 	}
 }
 
+// WithTracingSampler allows to set a custom sampler for the tracer provider.
+// If set, it takes precedence over the sampling rate configured via WithTracingSamplingRate.
+func WithTracingSampler(sampler sdktrace.Sampler) TracerProviderOption {
+	return func(c *tracerProviderConfig) {
+		c.sampler = sampler
+	}
+}
+
 // WithTracingSyncer lets you register the exporter with a synchronous SimpleSpanProcessor (e.g. instead of a batching
 // asynchronous one).
 // NOT RECOMMENDED FOR PRODUCTION USE (use for testing and debugging only).
@@ -138,6 +148,14 @@ func WithPrometheusExporter(registerer prometheus.Registerer) MeterProviderOptio
 	}
 }
 
+// WithJSONLinesExporter allows to write the metrics as OTLP-JSON lines (e.g. to a file or to stdout) instead of
+// exporting them to a collector, see otlpjson.NewMetricExporter
+func WithJSONLinesExporter(lw *otlpjson.LineWriter) MeterProviderOption {
+	return func(c *meterProviderConfig) {
+		c.jsonLinesWriter = lw
+	}
+}
+
 // WithDefaultHistogramBucketBoundaries lets you overwrite the default buckets for all histograms.
 func WithDefaultHistogramBucketBoundaries(boundaries []float64) MeterProviderOption {
 	return func(c *meterProviderConfig) {
@@ -18,6 +18,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/khulnasoft/go-kit/stats/internal/otel/otlpjson"
 	"github.com/khulnasoft/go-kit/stats/internal/otel/prometheus"
 )
 
@@ -117,9 +118,13 @@ func (m *Manager) buildTracerProviderOptions(
 	c *config,
 	res *resource.Resource, exp sdktrace.SpanExporter,
 ) []sdktrace.TracerProviderOption {
+	sampler := c.tracerProviderConfig.sampler
+	if sampler == nil {
+		sampler = sdktrace.TraceIDRatioBased(c.tracerProviderConfig.samplingRate)
+	}
 	opts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(c.tracerProviderConfig.samplingRate)),
+		sdktrace.WithSampler(sampler),
 	}
 
 	if c.tracerProviderConfig.withSyncer {
@@ -134,15 +139,33 @@ func (m *Manager) buildTracerProviderOptions(
 func (m *Manager) buildMeterProvider(
 	ctx context.Context, c config, res *resource.Resource,
 ) (*sdkmetric.MeterProvider, error) {
-	if c.meterProviderConfig.grpcEndpoint == nil && c.meterProviderConfig.prometheusRegisterer == nil {
-		return nil, fmt.Errorf("no grpc endpoint or prometheus registerer to initialize meter provider")
+	var exporters int
+	for _, configured := range []bool{
+		c.meterProviderConfig.grpcEndpoint != nil,
+		c.meterProviderConfig.prometheusRegisterer != nil,
+		c.meterProviderConfig.jsonLinesWriter != nil,
+	} {
+		if configured {
+			exporters++
+		}
 	}
-	if c.meterProviderConfig.grpcEndpoint != nil && c.meterProviderConfig.prometheusRegisterer != nil {
-		return nil, fmt.Errorf("cannot initialize meter provider with both grpc endpoint and prometheus registerer")
+	if exporters == 0 {
+		return nil, fmt.Errorf("no grpc endpoint, prometheus registerer or json lines writer to initialize meter provider")
+	}
+	if exporters > 1 {
+		return nil, fmt.Errorf("cannot initialize meter provider with more than one of grpc endpoint, " +
+			"prometheus registerer and json lines writer")
 	}
 	if c.meterProviderConfig.prometheusRegisterer != nil {
 		return m.buildPrometheusMeterProvider(c, res)
 	}
+	if c.meterProviderConfig.jsonLinesWriter != nil {
+		reader := sdkmetric.NewPeriodicReader(
+			otlpjson.NewMetricExporter(c.meterProviderConfig.jsonLinesWriter),
+			sdkmetric.WithInterval(c.meterProviderConfig.exportsInterval),
+		)
+		return sdkmetric.NewMeterProvider(m.getMeterProviderOptions(c, res, reader)...), nil
+	}
 	return m.buildOTLPMeterProvider(ctx, c, res)
 }
 
@@ -283,6 +306,7 @@ type tracerProviderConfig struct {
 	enabled            bool
 	global             bool
 	samplingRate       float64
+	sampler            sdktrace.Sampler
 	textMapPropagator  propagation.TextMapPropagator
 	customSpanExporter SpanExporter
 	withSyncer         bool
@@ -303,6 +327,7 @@ type meterProviderConfig struct {
 	grpcEndpoint            *string
 	prometheusRegisterer    promClient.Registerer
 	otlpMetricGRPCOptions   []otlpmetricgrpc.Option
+	jsonLinesWriter         *otlpjson.LineWriter
 }
 
 type logger interface {
@@ -0,0 +1,111 @@
+// Package otlpjson implements OpenTelemetry exporters writing OTLP-JSON lines, i.e. one JSON encoded
+// ExportTraceServiceRequest or ExportMetricsServiceRequest per line, for local development and debugging
+// (see https://opentelemetry.io/docs/specs/otel/protocol/file-exporter/).
+package otlpjson
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	metricscollectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	tracecollectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// LineWriter writes JSON encoded protobuf messages, one per line. It is safe for concurrent use, so that the
+// trace and metric exporters can share the same underlying writer (e.g. os.Stdout).
+type LineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLineWriter returns a LineWriter writing to w
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{w: w}
+}
+
+func (lw *LineWriter) write(msg proto.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshalling %T: %w", msg, err)
+	}
+	data = append(data, '\n')
+
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if _, err := lw.w.Write(data); err != nil {
+		return fmt.Errorf("writing %T: %w", msg, err)
+	}
+	return nil
+}
+
+// NewSpanExporter returns a span exporter writing an ExportTraceServiceRequest per exported batch of spans
+func NewSpanExporter(ctx context.Context, lw *LineWriter) (sdktrace.SpanExporter, error) {
+	return otlptrace.New(ctx, &traceClient{lw: lw})
+}
+
+// traceClient is an otlptrace.Client writing the spans instead of sending them to a collector
+type traceClient struct{ lw *LineWriter }
+
+func (*traceClient) Start(context.Context) error { return nil }
+func (*traceClient) Stop(context.Context) error  { return nil }
+
+func (c *traceClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	if len(protoSpans) == 0 {
+		return nil
+	}
+	return c.lw.write(&tracecollectorpb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+}
+
+// NewMetricExporter returns a metric exporter writing an ExportMetricsServiceRequest per export,
+// using cumulative temporality and the default aggregations
+func NewMetricExporter(lw *LineWriter) sdkmetric.Exporter {
+	return &metricExporter{lw: lw}
+}
+
+type metricExporter struct {
+	lw *LineWriter
+
+	mu       sync.Mutex
+	shutdown bool
+}
+
+func (*metricExporter) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(k)
+}
+
+func (*metricExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.shutdown {
+		return fmt.Errorf("exporter is shut down")
+	}
+	return e.lw.write(&metricscollectorpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{resourceMetrics(rm)},
+	})
+}
+
+func (*metricExporter) ForceFlush(ctx context.Context) error { return ctx.Err() }
+
+func (e *metricExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return ctx.Err()
+}
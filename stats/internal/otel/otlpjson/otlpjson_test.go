@@ -0,0 +1,155 @@
+package otlpjson_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	metricscollectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/khulnasoft/go-kit/stats/internal/otel/otlpjson"
+)
+
+func TestSpanExporter(t *testing.T) {
+	var buf bytes.Buffer
+	exporter, err := otlpjson.NewSpanExporter(context.Background(), otlpjson.NewLineWriter(&buf))
+	require.NoError(t, err)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String("my-service"))),
+	)
+	tracer := tp.Tracer("test")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx, root := tracer.Start(context.Background(), "GET /jobs",
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithTimestamp(start),
+	)
+	_, first := tracer.Start(ctx, "sql.get_job",
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(start.Add(time.Millisecond)),
+		trace.WithAttributes(attribute.String("db.statement", "SELECT ?"), attribute.Int64Slice("ids", []int64{1, 2})),
+	)
+	first.End(trace.WithTimestamp(start.Add(3 * time.Millisecond)))
+	_, second := tracer.Start(ctx, "redis.get",
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(start.Add(4*time.Millisecond)),
+	)
+	second.RecordError(errors.New("boom"))
+	second.SetStatus(codes.Error, "boom")
+	second.End(trace.WithTimestamp(start.Add(5 * time.Millisecond)))
+	root.End(trace.WithTimestamp(start.Add(10 * time.Millisecond)))
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	require.Equal(t, 3, strings.Count(buf.String(), "\n"), "one line per exported batch")
+
+	spans, err := otlpjson.ReadSpans(strings.NewReader(buf.String() + "\n"))
+	require.NoError(t, err)
+	require.Len(t, spans, 3)
+	roots := otlpjson.BuildTrees(spans)
+	require.Len(t, roots, 1)
+	require.Equal(t, "GET /jobs", roots[0].Name)
+	require.Equal(t, 10*time.Millisecond, roots[0].Duration())
+	require.Len(t, roots[0].Children, 2)
+
+	var out bytes.Buffer
+	require.NoError(t, otlpjson.PrintTrees(&out, roots, false))
+	traceID := root.SpanContext().TraceID().String()
+	require.Equal(t, "trace "+traceID+`
+└─ GET /jobs [server] 10ms (my-service)
+   ├─ sql.get_job [client] 2ms (my-service)
+   └─ redis.get [client] 1ms (my-service) ERROR boom
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, otlpjson.PrintTrees(&out, roots, true))
+	require.Equal(t, "trace "+traceID+`
+└─ GET /jobs [server] 10ms (my-service)
+   ├─ sql.get_job [client] 2ms (my-service)
+   │  · db.statement=SELECT ?
+   │  · ids=[1,2]
+   └─ redis.get [client] 1ms (my-service) ERROR boom
+      · event exception: boom
+`, out.String())
+
+	_, err = otlpjson.ReadSpans(strings.NewReader("{invalid"))
+	require.ErrorContains(t, err, "line 1")
+}
+
+func TestMetricExporter(t *testing.T) {
+	var buf bytes.Buffer
+	reader := sdkmetric.NewPeriodicReader(otlpjson.NewMetricExporter(otlpjson.NewLineWriter(&buf)),
+		sdkmetric.WithInterval(time.Hour),
+	)
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String("my-service"))),
+	)
+	meter := mp.Meter("test")
+
+	counter, err := meter.Int64Counter("jobs")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 3)
+	histogram, err := meter.Float64Histogram("latency")
+	require.NoError(t, err)
+	histogram.Record(context.Background(), 0.5)
+	histogram.Record(context.Background(), 2)
+	gauge, err := meter.Float64Gauge("queue")
+	require.NoError(t, err)
+	gauge.Record(context.Background(), 7, metricAttributes("tag", "value"))
+
+	require.NoError(t, mp.ForceFlush(context.Background()))
+	require.NoError(t, mp.Shutdown(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.GreaterOrEqual(t, len(lines), 1)
+	var req metricscollectorpb.ExportMetricsServiceRequest
+	require.NoError(t, protojson.Unmarshal([]byte(lines[0]), &req))
+	require.Len(t, req.ResourceMetrics, 1)
+	require.Equal(t, "service.name", req.ResourceMetrics[0].GetResource().GetAttributes()[0].GetKey())
+
+	metrics := make(map[string]*metricpb.Metric)
+	for _, sm := range req.ResourceMetrics[0].GetScopeMetrics() {
+		require.Equal(t, "test", sm.GetScope().GetName())
+		for _, m := range sm.GetMetrics() {
+			metrics[m.GetName()] = m
+		}
+	}
+	require.Len(t, metrics, 3)
+
+	jobs := metrics["jobs"].GetSum()
+	require.True(t, jobs.GetIsMonotonic())
+	require.Equal(t, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, jobs.GetAggregationTemporality())
+	require.EqualValues(t, 3, jobs.GetDataPoints()[0].GetAsInt())
+
+	latency := metrics["latency"].GetHistogram().GetDataPoints()[0]
+	require.EqualValues(t, 2, latency.GetCount())
+	require.Equal(t, 2.5, latency.GetSum())
+	require.Equal(t, 0.5, latency.GetMin())
+	require.Equal(t, 2.0, latency.GetMax())
+	require.Len(t, latency.GetBucketCounts(), len(latency.GetExplicitBounds())+1)
+
+	queue := metrics["queue"].GetGauge().GetDataPoints()[0]
+	require.Equal(t, 7.0, queue.GetAsDouble())
+	require.Equal(t, "tag", queue.GetAttributes()[0].GetKey())
+	require.Equal(t, "value", queue.GetAttributes()[0].GetValue().GetStringValue())
+}
+
+func metricAttributes(kv ...string) otelmetric.MeasurementOption {
+	attrs := make([]attribute.KeyValue, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		attrs = append(attrs, attribute.String(kv[i], kv[i+1]))
+	}
+	return otelmetric.WithAttributes(attrs...)
+}
@@ -0,0 +1,235 @@
+package otlpjson
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	tracecollectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxLineSize is the maximum size of a line read by ReadSpans
+const maxLineSize = 64 * 1024 * 1024
+
+// Span is a span read from OTLP-JSON lines, see ReadSpans
+type Span struct {
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Name          string
+	Kind          string
+	Service       string
+	Start         time.Time
+	End           time.Time
+	StatusCode    string
+	StatusMessage string
+	Attributes    map[string]string
+	Events        []string
+
+	Children []*Span
+}
+
+// Duration returns the duration of the span
+func (s *Span) Duration() time.Duration { return s.End.Sub(s.Start) }
+
+// ReadSpans reads the spans of the OTLP-JSON lines written by a span exporter (see NewSpanExporter).
+// Empty lines are ignored.
+func ReadSpans(r io.Reader) ([]*Span, error) {
+	var spans []*Span
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+		var req tracecollectorpb.ExportTraceServiceRequest
+		if err := protojson.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for _, rs := range req.GetResourceSpans() {
+			service := attributeValue(rs.GetResource().GetAttributes(), "service.name")
+			for _, ss := range rs.GetScopeSpans() {
+				for _, s := range ss.GetSpans() {
+					spans = append(spans, newSpan(s, service))
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return spans, nil
+}
+
+func newSpan(s *tracepb.Span, service string) *Span {
+	span := &Span{
+		TraceID:       hex.EncodeToString(s.GetTraceId()),
+		SpanID:        hex.EncodeToString(s.GetSpanId()),
+		ParentSpanID:  hex.EncodeToString(s.GetParentSpanId()),
+		Name:          s.GetName(),
+		Kind:          strings.ToLower(strings.TrimPrefix(s.GetKind().String(), "SPAN_KIND_")),
+		Service:       service,
+		Start:         time.Unix(0, int64(s.GetStartTimeUnixNano())),
+		End:           time.Unix(0, int64(s.GetEndTimeUnixNano())),
+		StatusCode:    strings.TrimPrefix(s.GetStatus().GetCode().String(), "STATUS_CODE_"),
+		StatusMessage: s.GetStatus().GetMessage(),
+		Attributes:    make(map[string]string, len(s.GetAttributes())),
+	}
+	for _, kv := range s.GetAttributes() {
+		span.Attributes[kv.GetKey()] = formatValue(kv.GetValue())
+	}
+	for _, e := range s.GetEvents() {
+		event := e.GetName()
+		if msg := attributeValue(e.GetAttributes(), "exception.message"); msg != "" {
+			event += ": " + msg
+		}
+		span.Events = append(span.Events, event)
+	}
+	return span
+}
+
+// BuildTrees links the spans to their parents, returning the root spans sorted by start time.
+// Spans whose parent is missing (e.g. because it was not sampled or belongs to another service) are roots.
+func BuildTrees(spans []*Span) []*Span {
+	byID := make(map[string]*Span, len(spans))
+	for _, s := range spans {
+		s.Children = nil
+		byID[s.TraceID+s.SpanID] = s
+	}
+	var roots []*Span
+	for _, s := range spans {
+		if parent, ok := byID[s.TraceID+s.ParentSpanID]; ok && s.ParentSpanID != "" && parent != s {
+			parent.Children = append(parent.Children, s)
+			continue
+		}
+		roots = append(roots, s)
+	}
+	sortByStart(roots)
+	for _, s := range spans {
+		sortByStart(s.Children)
+	}
+	return roots
+}
+
+func sortByStart(spans []*Span) {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+}
+
+// PrintTrees pretty-prints span trees (see BuildTrees) grouped by trace, in order of appearance, e.g.
+//
+//	trace 4bf92f3577b34da6a3ce929d0e0e4736
+//	└─ GET /jobs [server] 12.3ms (my-service)
+//	   ├─ sql.get_job [client] 2.1ms
+//	   └─ redis.get [client] 400µs ERROR boom
+//
+// Attributes are printed as well if withAttributes is true.
+func PrintTrees(w io.Writer, roots []*Span, withAttributes bool) error {
+	var (
+		traceIDs []string
+		byTrace  = make(map[string][]*Span)
+	)
+	for _, root := range roots {
+		if _, ok := byTrace[root.TraceID]; !ok {
+			traceIDs = append(traceIDs, root.TraceID)
+		}
+		byTrace[root.TraceID] = append(byTrace[root.TraceID], root)
+	}
+
+	p := &treePrinter{w: w, withAttributes: withAttributes}
+	for _, traceID := range traceIDs {
+		p.printf("trace %s\n", traceID)
+		traceRoots := byTrace[traceID]
+		for i, root := range traceRoots {
+			p.print(root, "", i == len(traceRoots)-1)
+		}
+	}
+	return p.err
+}
+
+type treePrinter struct {
+	w              io.Writer
+	withAttributes bool
+	err            error
+}
+
+func (p *treePrinter) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func (p *treePrinter) print(s *Span, indent string, last bool) {
+	branch, childIndent := "├─ ", indent+"│  "
+	if last {
+		branch, childIndent = "└─ ", indent+"   "
+	}
+
+	line := fmt.Sprintf("%s [%s] %s", s.Name, s.Kind, s.Duration())
+	if s.Service != "" {
+		line += " (" + s.Service + ")"
+	}
+	if s.StatusCode == "ERROR" {
+		line += " ERROR"
+		if s.StatusMessage != "" {
+			line += " " + s.StatusMessage
+		}
+	}
+	p.printf("%s%s%s\n", indent, branch, line)
+
+	if p.withAttributes {
+		keys := make([]string, 0, len(s.Attributes))
+		for k := range s.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p.printf("%s· %s=%s\n", childIndent, k, s.Attributes[k])
+		}
+		for _, e := range s.Events {
+			p.printf("%s· event %s\n", childIndent, e)
+		}
+	}
+
+	for i, child := range s.Children {
+		p.print(child, childIndent, i == len(s.Children)-1)
+	}
+}
+
+func attributeValue(attrs []*commonpb.KeyValue, key string) string {
+	for _, kv := range attrs {
+		if kv.GetKey() == key {
+			return formatValue(kv.GetValue())
+		}
+	}
+	return ""
+}
+
+func formatValue(v *commonpb.AnyValue) string {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprint(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprint(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(v.DoubleValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]string, 0, len(v.ArrayValue.GetValues()))
+		for _, av := range v.ArrayValue.GetValues() {
+			values = append(values, formatValue(av))
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	default:
+		return ""
+	}
+}
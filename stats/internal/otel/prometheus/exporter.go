@@ -15,10 +15,14 @@
 //     see here: https://github.com/open-telemetry/opentelemetry-go/blob/v1.14.0/exporters/prometheus/exporter.go#L393
 //
 //  4. removed unnecessary otel_scope_info metric
+//
+//  5. exemplars are exported with the trace_id and span_id labels only (backported from later versions of the
+//     upstream exporter). They are only exposed when the OpenMetrics format is negotiated.
 package prometheus
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"sort"
@@ -43,6 +47,9 @@ import (
 const (
 	targetInfoMetricName  = "target_info"
 	targetInfoDescription = "Target metadata"
+
+	traceIDExemplarKey = "trace_id"
+	spanIDExemplarKey  = "span_id"
 )
 
 // Exporter is a Prometheus Exporter that embeds the OTel metric.Reader
@@ -182,7 +189,7 @@ func addHistogramMetric[N int64 | float64](
 			otel.Handle(err)
 			continue
 		}
-		ch <- m
+		ch <- addExemplars(m, dp.Exemplars)
 	}
 }
 
@@ -214,6 +221,9 @@ func addSumMetric[N int64 | float64](
 			otel.Handle(err)
 			continue
 		}
+		if sum.IsMonotonic {
+			m = addExemplars(m, dp.Exemplars)
+		}
 		ch <- m
 	}
 }
@@ -243,6 +253,34 @@ func addGaugeMetric[N int64 | float64](
 	}
 }
 
+// addExemplars attaches the given exemplars to m. Exemplars without a trace are skipped since they would not
+// link to anything. If the exemplars cannot be attached, m is returned as is.
+func addExemplars[N int64 | float64](m prometheus.Metric, exemplars []metricdata.Exemplar[N]) prometheus.Metric {
+	promExemplars := make([]prometheus.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		if len(e.TraceID) == 0 {
+			continue
+		}
+		promExemplars = append(promExemplars, prometheus.Exemplar{
+			Value:     float64(e.Value),
+			Timestamp: e.Time,
+			Labels: prometheus.Labels{
+				traceIDExemplarKey: hex.EncodeToString(e.TraceID),
+				spanIDExemplarKey:  hex.EncodeToString(e.SpanID),
+			},
+		})
+	}
+	if len(promExemplars) == 0 {
+		return m
+	}
+	mwe, err := prometheus.NewMetricWithExemplars(m, promExemplars...)
+	if err != nil {
+		otel.Handle(err)
+		return m
+	}
+	return mwe
+}
+
 // getAttrs parses the attribute.Set to two lists of matching Prometheus-style
 // keys and values. It sanitizes invalid characters and handles duplicate keys
 // (due to sanitization) by sorting and concatenating the values following the spec.
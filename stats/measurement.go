@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -19,12 +20,18 @@ type Gauge interface {
 // Histogram represents a histogram metric
 type Histogram interface {
 	Observe(value float64)
+	// ObserveCtx is like Observe but if ctx carries a sampled span, its trace and span IDs are attached as an exemplar
+	ObserveCtx(ctx context.Context, value float64)
 }
 
 // Timer represents a timer metric
 type Timer interface {
 	SendTiming(duration time.Duration)
+	// SendTimingCtx is like SendTiming but if ctx carries a sampled span, its trace and span IDs are attached as an exemplar
+	SendTimingCtx(ctx context.Context, duration time.Duration)
 	Since(start time.Time)
+	// SinceCtx is like Since but if ctx carries a sampled span, its trace and span IDs are attached as an exemplar
+	SinceCtx(ctx context.Context, start time.Time)
 	RecordDuration() func()
 }
 
@@ -61,6 +68,11 @@ func (m *genericMeasurement) Observe(_ float64) {
 	panic(fmt.Errorf("operation Observe not supported for measurement type:%s", m.statType))
 }
 
+// ObserveCtx default behavior is to panic as not supported operation
+func (m *genericMeasurement) ObserveCtx(_ context.Context, _ float64) {
+	panic(fmt.Errorf("operation ObserveCtx not supported for measurement type:%s", m.statType))
+}
+
 // Start default behavior is to panic as not supported operation
 func (m *genericMeasurement) Start() {
 	panic(fmt.Errorf("operation Start not supported for measurement type:%s", m.statType))
@@ -75,11 +87,21 @@ func (m *genericMeasurement) SendTiming(_ time.Duration) {
 	panic(fmt.Errorf("operation SendTiming not supported for measurement type:%s", m.statType))
 }
 
+// SendTimingCtx default behavior is to panic as not supported operation
+func (m *genericMeasurement) SendTimingCtx(_ context.Context, _ time.Duration) {
+	panic(fmt.Errorf("operation SendTimingCtx not supported for measurement type:%s", m.statType))
+}
+
 // Since default behavior is to panic as not supported operation
 func (m *genericMeasurement) Since(_ time.Time) {
 	panic(fmt.Errorf("operation Since not supported for measurement type:%s", m.statType))
 }
 
+// SinceCtx default behavior is to panic as not supported operation
+func (m *genericMeasurement) SinceCtx(_ context.Context, _ time.Time) {
+	panic(fmt.Errorf("operation SinceCtx not supported for measurement type:%s", m.statType))
+}
+
 // RecordDuration default behavior is to panic as not supported operation
 func (m *genericMeasurement) RecordDuration() func() {
 	panic(fmt.Errorf("operation RecordDuration not supported for measurement type:%s", m.statType))
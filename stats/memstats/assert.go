@@ -0,0 +1,130 @@
+package memstats
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+// UpdateGoldenFilesEnv is the environment variable which, when set to "true", makes RequireGoldenFile
+// write the golden files instead of comparing them
+const UpdateGoldenFilesEnv = "MEMSTATS_UPDATE_GOLDEN"
+
+// RequireCounter requires the counter with the given name and tags to exist with the wanted value
+func RequireCounter(t testing.TB, store *Store, name string, tags stats.Tags, want float64) {
+	t.Helper()
+	m := requireMeasurement(t, store, name, tags, stats.CountType)
+	require.Equalf(t, want, m.LastValue(), "counter %q with tags %v", name, tags)
+}
+
+// RequireGauge requires the gauge with the given name and tags to exist with the wanted value
+func RequireGauge(t testing.TB, store *Store, name string, tags stats.Tags, want float64) {
+	t.Helper()
+	m := requireMeasurement(t, store, name, tags, stats.GaugeType)
+	require.Equalf(t, want, m.LastValue(), "gauge %q with tags %v", name, tags)
+}
+
+// RequireHistogram requires the histogram with the given name and tags to exist with the wanted observed values
+func RequireHistogram(t testing.TB, store *Store, name string, tags stats.Tags, want []float64) {
+	t.Helper()
+	m := requireMeasurement(t, store, name, tags, stats.HistogramType)
+	require.Equalf(t, want, m.Values(), "histogram %q with tags %v", name, tags)
+}
+
+// RequireTimerCount requires the timer with the given name and tags to exist with the wanted number of durations
+func RequireTimerCount(t testing.TB, store *Store, name string, tags stats.Tags, want int) {
+	t.Helper()
+	m := requireMeasurement(t, store, name, tags, stats.TimerType)
+	require.Lenf(t, m.Durations(), want, "timer %q with tags %v", name, tags)
+}
+
+// RequireNoMeasurement requires no measurement with the given name and tags to exist
+func RequireNoMeasurement(t testing.TB, store *Store, name string, tags stats.Tags) {
+	t.Helper()
+	require.Nilf(t, store.Get(name, tags), "unexpected measurement %q with tags %v", name, tags)
+}
+
+// EventuallyCounter requires the counter with the given name and tags to reach the wanted value within waitFor,
+// checking it every tick
+func EventuallyCounter(t testing.TB, store *Store, name string, tags stats.Tags, want float64, waitFor, tick time.Duration) {
+	t.Helper()
+	eventually(t, store, name, tags, stats.CountType, want, waitFor, tick)
+}
+
+// EventuallyGauge requires the gauge with the given name and tags to reach the wanted value within waitFor,
+// checking it every tick
+func EventuallyGauge(t testing.TB, store *Store, name string, tags stats.Tags, want float64, waitFor, tick time.Duration) {
+	t.Helper()
+	eventually(t, store, name, tags, stats.GaugeType, want, waitFor, tick)
+}
+
+func eventually(t testing.TB, store *Store, name string, tags stats.Tags, mType string, want float64, waitFor, tick time.Duration) {
+	t.Helper()
+	require.EventuallyWithTf(t, func(c *assert.CollectT) {
+		m := store.Get(name, tags)
+		if !assert.NotNil(c, m, "measurement not found") {
+			return
+		}
+		if !assert.Equal(c, mType, m.mType, "measurement type") {
+			return
+		}
+		assert.Equal(c, want, m.LastValue())
+	}, waitFor, tick, "%s %q with tags %v", mType, name, tags)
+}
+
+// RequireOnlyNames requires all the measurements of the store to have one of the given names,
+// so that tests can detect unexpected measurements
+func RequireOnlyNames(t testing.TB, store *Store, names ...string) {
+	t.Helper()
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[name] = struct{}{}
+	}
+	unexpected := make(map[string]struct{})
+	for _, m := range store.GetAll() {
+		if _, ok := allowed[m.Name]; !ok {
+			unexpected[m.Name] = struct{}{}
+		}
+	}
+	unexpectedNames := make([]string, 0, len(unexpected))
+	for name := range unexpected {
+		unexpectedNames = append(unexpectedNames, name)
+	}
+	sort.Strings(unexpectedNames)
+	require.Emptyf(t, unexpectedNames, "unexpected measurements, allowed ones are %v", names)
+}
+
+// RequireGoldenFile requires the Prometheus text export of the store (see WritePrometheusText) to match the content
+// of the golden file at path. If names are provided, only the measurements with these names are compared.
+// Golden files are written instead of being compared if the UpdateGoldenFilesEnv environment variable is "true", e.g.
+//
+//	MEMSTATS_UPDATE_GOLDEN=true go test ./...
+func RequireGoldenFile(t testing.TB, store *Store, path string, names ...string) {
+	t.Helper()
+	got, err := store.PrometheusText(names...)
+	require.NoError(t, err)
+
+	if os.Getenv(UpdateGoldenFilesEnv) == "true" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoErrorf(t, err, "reading golden file, set %s=true to create it", UpdateGoldenFilesEnv)
+	require.Equalf(t, string(want), got, "golden file %q mismatch, set %s=true to update it", path, UpdateGoldenFilesEnv)
+}
+
+func requireMeasurement(t testing.TB, store *Store, name string, tags stats.Tags, mType string) *Measurement {
+	t.Helper()
+	m := store.Get(name, tags)
+	require.NotNilf(t, m, "measurement %q with tags %v not found, measurements with this name: %v", name, tags, store.getAllByName(name))
+	require.Equalf(t, mType, m.mType, "measurement %q with tags %v", name, tags)
+	return m
+}
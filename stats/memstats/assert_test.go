@@ -0,0 +1,151 @@
+package memstats_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+// mockT is a testing.TB recording failures instead of failing the test
+type mockT struct {
+	testing.TB
+	mu     sync.Mutex
+	failed bool
+	errors []string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failed = true
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func (m *mockT) FailNow() {
+	m.mu.Lock()
+	m.failed = true
+	m.mu.Unlock()
+	runtime.Goexit()
+}
+
+// fails returns true if fn fails the given testing.TB
+func fails(t *testing.T, fn func(t testing.TB)) bool {
+	mt := &mockT{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(mt)
+	}()
+	<-done
+	return mt.failed
+}
+
+func TestAssertions(t *testing.T) {
+	store, err := memstats.New()
+	require.NoError(t, err)
+	tags := stats.Tags{"workspaceId": "ws"}
+	store.NewTaggedStat("counter", stats.CountType, tags).Count(2)
+	store.NewTaggedStat("gauge", stats.GaugeType, tags).Gauge(10)
+	store.NewTaggedStat("histogram", stats.HistogramType, tags).Observe(1)
+	store.NewTaggedStat("timer", stats.TimerType, tags).SendTiming(time.Second)
+
+	t.Run("counter", func(t *testing.T) {
+		memstats.RequireCounter(t, store, "counter", tags, 2)
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireCounter(t, store, "counter", tags, 1) }))
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireCounter(t, store, "counter", nil, 2) }), "different tags")
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireCounter(t, store, "gauge", tags, 10) }), "different type")
+	})
+
+	t.Run("gauge", func(t *testing.T) {
+		memstats.RequireGauge(t, store, "gauge", tags, 10)
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireGauge(t, store, "gauge", tags, 1) }))
+	})
+
+	t.Run("histogram and timer", func(t *testing.T) {
+		memstats.RequireHistogram(t, store, "histogram", tags, []float64{1})
+		memstats.RequireTimerCount(t, store, "timer", tags, 1)
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireHistogram(t, store, "histogram", tags, []float64{2}) }))
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireTimerCount(t, store, "timer", tags, 2) }))
+	})
+
+	t.Run("no measurement", func(t *testing.T) {
+		memstats.RequireNoMeasurement(t, store, "counter", nil)
+		require.True(t, fails(t, func(t testing.TB) { memstats.RequireNoMeasurement(t, store, "counter", tags) }))
+	})
+
+	t.Run("eventually", func(t *testing.T) {
+		counter := store.NewTaggedStat("eventual_counter", stats.CountType, tags)
+		gauge := store.NewTaggedStat("eventual_gauge", stats.GaugeType, tags)
+		go func() {
+			for i := 0; i < 3; i++ {
+				time.Sleep(time.Millisecond)
+				counter.Increment()
+			}
+			gauge.Gauge(5)
+		}()
+		memstats.EventuallyCounter(t, store, "eventual_counter", tags, 3, time.Second, time.Millisecond)
+		memstats.EventuallyGauge(t, store, "eventual_gauge", tags, 5, time.Second, time.Millisecond)
+		require.True(t, fails(t, func(t testing.TB) {
+			memstats.EventuallyCounter(t, store, "eventual_counter", tags, 4, 10*time.Millisecond, time.Millisecond)
+		}))
+	})
+
+	t.Run("only names", func(t *testing.T) {
+		store, err := memstats.New()
+		require.NoError(t, err)
+		store.NewStat("expected", stats.CountType).Increment()
+		store.NewTaggedStat("expected", stats.CountType, tags).Increment()
+		memstats.RequireOnlyNames(t, store, "expected", "optional")
+
+		store.NewStat("unexpected", stats.GaugeType).Gauge(1)
+		mt := &mockT{TB: t}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			memstats.RequireOnlyNames(mt, store, "expected")
+		}()
+		<-done
+		require.True(t, mt.failed)
+		require.Len(t, mt.errors, 1)
+		require.Contains(t, mt.errors[0], "unexpected")
+	})
+}
+
+func TestRequireGoldenFile(t *testing.T) {
+	store, err := memstats.New()
+	require.NoError(t, err)
+	store.NewTaggedStat("jobs.processed", stats.CountType, stats.Tags{"workspaceId": "ws"}).Count(2)
+	store.NewStat("queue_size", stats.GaugeType).Gauge(3)
+	latency := store.NewTaggedStat("latency", stats.HistogramType, stats.Tags{"destination.type": "S3"})
+	latency.Observe(1)
+	latency.Observe(2.5)
+	store.NewStat("duration", stats.TimerType).SendTiming(1500 * time.Millisecond)
+
+	memstats.RequireGoldenFile(t, store, filepath.Join("testdata", "golden.prom"))
+	memstats.RequireGoldenFile(t, store, filepath.Join("testdata", "golden_queue_size.prom"), "queue_size")
+
+	path := filepath.Join(t.TempDir(), "new", "golden.prom")
+	require.True(t, fails(t, func(t testing.TB) { memstats.RequireGoldenFile(t, store, path) }), "missing golden file")
+	t.Setenv(memstats.UpdateGoldenFilesEnv, "true")
+	memstats.RequireGoldenFile(t, store, path)
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	want, err := os.ReadFile(filepath.Join("testdata", "golden.prom"))
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(golden))
+
+	store.NewStat("queue_size", stats.GaugeType).Gauge(4)
+	t.Setenv(memstats.UpdateGoldenFilesEnv, "")
+	require.True(t, fails(t, func(t testing.TB) { memstats.RequireGoldenFile(t, store, path) }), "golden file mismatch")
+}
@@ -0,0 +1,113 @@
+package memstats
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+// WritePrometheusText writes the measurements of the store in the Prometheus text exposition format, sorted by
+// name and tags so that the output can be compared with golden files, see RequireGoldenFile.
+// If names are provided, only the measurements with these names are written.
+//
+// Counters and gauges are written as Prometheus counters and gauges, while histograms and timers are written as
+// summaries without quantiles, i.e. with their count and sum only (timers in seconds).
+// Names and tag keys are escaped to be valid Prometheus names, i.e. invalid characters are replaced with "_".
+func (ms *Store) WritePrometheusText(w io.Writer, names ...string) error {
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[name] = struct{}{}
+	}
+
+	families := make(map[string]*dto.MetricFamily)
+	snapshot := ms.Snapshot()
+	for _, key := range snapshot.sortedKeys() {
+		e := snapshot.entries[key]
+		if _, ok := allowed[e.metric.Name]; len(allowed) > 0 && !ok {
+			continue
+		}
+
+		name := model.EscapeName(e.metric.Name, model.UnderscoreEscaping)
+		metric := &dto.Metric{Label: prometheusLabels(e.metric.Tags)}
+		var metricType dto.MetricType
+		switch e.mType {
+		case stats.CountType:
+			metricType = dto.MetricType_COUNTER
+			metric.Counter = &dto.Counter{Value: proto.Float64(e.metric.Value)}
+		case stats.GaugeType:
+			metricType = dto.MetricType_GAUGE
+			metric.Gauge = &dto.Gauge{Value: proto.Float64(e.metric.Value)}
+		case stats.HistogramType:
+			metricType = dto.MetricType_SUMMARY
+			var sum float64
+			for _, v := range e.metric.Values {
+				sum += v
+			}
+			metric.Summary = &dto.Summary{SampleCount: proto.Uint64(uint64(len(e.metric.Values))), SampleSum: proto.Float64(sum)}
+		case stats.TimerType:
+			metricType = dto.MetricType_SUMMARY
+			var sum float64
+			for _, d := range e.metric.Durations {
+				sum += d.Seconds()
+			}
+			metric.Summary = &dto.Summary{SampleCount: proto.Uint64(uint64(len(e.metric.Durations))), SampleSum: proto.Float64(sum)}
+		default:
+			return fmt.Errorf("unknown measurement type %q for %q", e.mType, e.metric.Name)
+		}
+
+		family, ok := families[name]
+		if !ok {
+			family = &dto.MetricFamily{Name: proto.String(name), Type: metricType.Enum()}
+			families[name] = family
+		} else if family.GetType() != metricType {
+			return fmt.Errorf("measurement %q has different types: %s and %s", e.metric.Name, family.GetType(), metricType)
+		}
+		family.Metric = append(family.Metric, metric)
+	}
+
+	familyNames := make([]string, 0, len(families))
+	for name := range families {
+		familyNames = append(familyNames, name)
+	}
+	sort.Strings(familyNames)
+	for _, name := range familyNames {
+		if _, err := expfmt.MetricFamilyToText(w, families[name]); err != nil {
+			return fmt.Errorf("writing %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// PrometheusText returns the measurements of the store in the Prometheus text exposition format,
+// see WritePrometheusText
+func (ms *Store) PrometheusText(names ...string) (string, error) {
+	var buf bytes.Buffer
+	if err := ms.WritePrometheusText(&buf, names...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func prometheusLabels(tags stats.Tags) []*dto.LabelPair {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	labels := make([]*dto.LabelPair, 0, len(tags))
+	for _, key := range keys {
+		labels = append(labels, &dto.LabelPair{
+			Name:  proto.String(model.EscapeName(key, model.UnderscoreEscaping)),
+			Value: proto.String(tags[key]),
+		})
+	}
+	return labels
+}
@@ -0,0 +1,112 @@
+package memstats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/khulnasoft/go-kit/stats"
+)
+
+// Snapshot is a point in time copy of all the measurements of a Store, see Store.Snapshot and Diff
+type Snapshot struct {
+	entries map[string]snapshotEntry
+}
+
+type snapshotEntry struct {
+	metric  Metric
+	mType   string
+	updates int
+}
+
+// Snapshot returns a copy of all the measurements currently in the store
+func (ms *Store) Snapshot() Snapshot {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	s := Snapshot{entries: make(map[string]snapshotEntry, len(ms.byKey))}
+	for key, m := range ms.byKey {
+		s.entries[key] = m.snapshot()
+	}
+	return s
+}
+
+func (m *Measurement) snapshot() snapshotEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := snapshotEntry{
+		metric:  Metric{Name: m.name, Tags: m.tags},
+		mType:   m.mType,
+		updates: len(m.values) + len(m.durations),
+	}
+	switch m.mType {
+	case stats.CountType, stats.GaugeType:
+		if len(m.values) > 0 {
+			e.metric.Value = m.values[len(m.values)-1]
+		}
+	case stats.HistogramType:
+		e.metric.Values = append([]float64{}, m.values...)
+	case stats.TimerType:
+		e.metric.Durations = append([]time.Duration{}, m.durations...)
+	}
+	return e
+}
+
+// Get returns the metric with the given name and tags, if it was in the store when the snapshot was taken
+func (s Snapshot) Get(name string, tags stats.Tags) (Metric, bool) {
+	e, ok := s.entries[name+tags.String()]
+	return e.metric, ok
+}
+
+// Metrics returns all the metrics of the snapshot, sorted like Store.GetAll
+func (s Snapshot) Metrics() []Metric {
+	metrics := make([]Metric, 0, len(s.entries))
+	for _, key := range s.sortedKeys() {
+		metrics = append(metrics, s.entries[key].metric)
+	}
+	return metrics
+}
+
+func (s Snapshot) sortedKeys() []string {
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Diff returns the metrics which have been created or updated between two snapshots of the same store, e.g.
+//
+//	before := store.Snapshot()
+//	doSomething()
+//	require.Equal(t, []memstats.Metric{{Name: "processed", Value: 1}}, memstats.Diff(before, store.Snapshot()))
+//
+// The returned metrics contain:
+//   - for counters, the increase of their value;
+//   - for gauges, their last value;
+//   - for histograms and timers, the values observed in between.
+func Diff(before, after Snapshot) []Metric {
+	var diff []Metric
+	for _, key := range after.sortedKeys() {
+		a := after.entries[key]
+		b, existed := before.entries[key]
+		if existed && a.updates == b.updates {
+			continue
+		}
+
+		m := Metric{Name: a.metric.Name, Tags: a.metric.Tags}
+		switch a.mType {
+		case stats.CountType:
+			m.Value = a.metric.Value - b.metric.Value
+		case stats.GaugeType:
+			m.Value = a.metric.Value
+		case stats.HistogramType:
+			m.Values = a.metric.Values[len(b.metric.Values):]
+		case stats.TimerType:
+			m.Durations = a.metric.Durations[len(b.metric.Durations):]
+		}
+		diff = append(diff, m)
+	}
+	return diff
+}
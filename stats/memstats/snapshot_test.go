@@ -0,0 +1,57 @@
+package memstats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+func TestSnapshot(t *testing.T) {
+	store, err := memstats.New()
+	require.NoError(t, err)
+	tags := stats.Tags{"workspaceId": "ws"}
+
+	counter := store.NewTaggedStat("counter", stats.CountType, tags)
+	counter.Count(2)
+	gauge := store.NewStat("gauge", stats.GaugeType)
+	gauge.Gauge(1)
+	histogram := store.NewStat("histogram", stats.HistogramType)
+	histogram.Observe(1)
+	timer := store.NewStat("timer", stats.TimerType)
+	timer.SendTiming(time.Second)
+	store.NewStat("untouched", stats.CountType).Increment()
+
+	before := store.Snapshot()
+	m, ok := before.Get("counter", tags)
+	require.True(t, ok)
+	require.Equal(t, memstats.Metric{Name: "counter", Tags: tags, Value: 2}, m)
+	_, ok = before.Get("counter", nil)
+	require.False(t, ok)
+	require.Equal(t, store.GetAll(), before.Metrics())
+
+	require.Empty(t, memstats.Diff(before, store.Snapshot()))
+
+	counter.Count(3)
+	gauge.Gauge(1) // same value, but still an update
+	histogram.Observe(2)
+	timer.SendTiming(2 * time.Second)
+	store.NewStat("new_counter", stats.CountType).Increment()
+
+	after := store.Snapshot()
+	require.Equal(t, []memstats.Metric{
+		{Name: "counter", Tags: tags, Value: 3},
+		{Name: "gauge", Value: 1},
+		{Name: "histogram", Values: []float64{2}},
+		{Name: "new_counter", Value: 1},
+		{Name: "timer", Durations: []time.Duration{2 * time.Second}},
+	}, memstats.Diff(before, after))
+
+	// snapshots are not affected by later updates
+	counter.Increment()
+	m, _ = after.Get("counter", tags)
+	require.Equal(t, 5.0, m.Value)
+}
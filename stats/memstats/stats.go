@@ -151,6 +151,11 @@ func (m *Measurement) Observe(value float64) {
 	m.values = append(m.values, value)
 }
 
+// ObserveCtx implements stats.Measurement
+func (m *Measurement) ObserveCtx(_ context.Context, value float64) {
+	m.Observe(value)
+}
+
 // Since implements stats.Measurement
 func (m *Measurement) Since(start time.Time) {
 	if m.mType != stats.TimerType {
@@ -160,6 +165,11 @@ func (m *Measurement) Since(start time.Time) {
 	m.SendTiming(m.now().Sub(start))
 }
 
+// SinceCtx implements stats.Measurement
+func (m *Measurement) SinceCtx(_ context.Context, start time.Time) {
+	m.Since(start)
+}
+
 // SendTiming implements stats.Measurement
 func (m *Measurement) SendTiming(duration time.Duration) {
 	if m.mType != stats.TimerType {
@@ -172,6 +182,11 @@ func (m *Measurement) SendTiming(duration time.Duration) {
 	m.durations = append(m.durations, duration)
 }
 
+// SendTimingCtx implements stats.Measurement
+func (m *Measurement) SendTimingCtx(_ context.Context, duration time.Duration) {
+	m.SendTiming(duration)
+}
+
 // RecordDuration implements stats.Measurement
 func (m *Measurement) RecordDuration() func() {
 	if m.mType != stats.TimerType {
@@ -274,6 +289,11 @@ func (ms *Store) NewSampledTaggedStat(name, statType string, tags stats.Tags) st
 	return m
 }
 
+// Scope implements stats.Stats
+func (ms *Store) Scope(prefix string, tags stats.Tags) stats.Stats {
+	return stats.Scope(ms, prefix, tags)
+}
+
 // Get the stored measurement with the name and tags.
 // If no measurement is found, nil is returned.
 func (ms *Store) Get(name string, tags stats.Tags) *Measurement {
@@ -2,6 +2,7 @@ package memstats_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -359,6 +360,64 @@ func TestStats(t *testing.T) {
 		}}, spans[2].Attributes, "unrelatedSpan attributes: %+v", spans[2].Attributes)
 	})
 
+	t.Run("with tracing errors, links and typed attributes", func(t *testing.T) {
+		store, err := memstats.New(memstats.WithTracing())
+		require.NoError(t, err)
+
+		tracer := store.NewTracer("my-tracer")
+		producer1Ctx, producer1 := tracer.Start(context.Background(), "producer1", stats.SpanKindProducer)
+		producer1.End()
+		_, producer2 := tracer.Start(context.Background(), "producer2", stats.SpanKindProducer)
+		producer2.End()
+		traceParent := stats.GetTraceParentFromContext(producer1Ctx)
+
+		_, job := tracer.Start(context.Background(), "job", stats.SpanKindConsumer,
+			stats.SpanWithLinks(stats.SpanLinkFromTraceParent(traceParent, stats.IntAttribute("jobs", 3))),
+			stats.SpanWithAttributes(stats.BoolAttribute("retry", true)),
+		)
+		job.AddLink(stats.SpanLink{SpanContext: producer2.SpanContext()})
+		job.SetTypedAttributes(
+			stats.Int64Attribute("size", 10),
+			stats.Float64Attribute("ratio", 0.5),
+			stats.StringSliceAttribute("destinations", []string{"a", "b"}),
+		)
+		job.RecordError(errors.New("some error"),
+			stats.SpanWithStackTrace(),
+			stats.SpanWithTags(stats.Tags{"tag1": "value1"}),
+		)
+		job.End()
+
+		spans, err := store.Spans()
+		require.NoError(t, err)
+		require.Len(t, spans, 3)
+		require.Equal(t, "job", spans[2].Name)
+
+		require.ElementsMatchf(t, []tracemodel.Attributes{
+			{Key: "retry", Value: tracemodel.Value{Type: "BOOL", Value: true}},
+			{Key: "size", Value: tracemodel.Value{Type: "INT64", Value: 10.0}},
+			{Key: "ratio", Value: tracemodel.Value{Type: "FLOAT64", Value: 0.5}},
+			{Key: "destinations", Value: tracemodel.Value{Type: "STRINGSLICE", Value: []any{"a", "b"}}},
+		}, spans[2].Attributes, "job attributes: %+v", spans[2].Attributes)
+
+		require.Len(t, spans[2].Links, 2)
+		require.Equal(t, spans[0].SpanContext.SpanID, spans[2].Links[0].SpanContext.SpanID)
+		require.Equal(t, spans[0].SpanContext.TraceID, spans[2].Links[0].SpanContext.TraceID)
+		require.Equal(t, []tracemodel.Attributes{
+			{Key: "jobs", Value: tracemodel.Value{Type: "INT64", Value: 3.0}},
+		}, spans[2].Links[0].Attributes)
+		require.Equal(t, spans[1].SpanContext.SpanID, spans[2].Links[1].SpanContext.SpanID)
+
+		require.Len(t, spans[2].Events, 1)
+		require.Equal(t, "exception", spans[2].Events[0].Name)
+		attrs := make(map[string]any)
+		for _, a := range spans[2].Events[0].Attributes {
+			attrs[a.Key] = a.Value.Value
+		}
+		require.Equal(t, "some error", attrs["exception.message"])
+		require.Equal(t, "value1", attrs["tag1"])
+		require.Contains(t, attrs["exception.stacktrace"], "memstats_test.TestStats")
+	})
+
 	t.Run("with tracing timestamps", func(t *testing.T) {
 		store, err := memstats.New(
 			memstats.WithNow(func() time.Time {
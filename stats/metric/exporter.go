@@ -0,0 +1,26 @@
+package metric
+
+import "time"
+
+// ExporterOption configures the exporters of a registry, see NewPrometheusCollector and RegisterOTelInstruments
+type ExporterOption func(*exporterConfig)
+
+type exporterConfig struct {
+	ttl time.Duration
+}
+
+// WithStaleTTL makes the exporter remove the measurements which have neither been retrieved nor changed their value
+// for the given ttl before reading the registry, see Registry.DeleteStale.
+func WithStaleTTL(ttl time.Duration) ExporterOption {
+	return func(c *exporterConfig) {
+		c.ttl = ttl
+	}
+}
+
+func newExporterConfig(opts []ExporterOption) exporterConfig {
+	var c exporterConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
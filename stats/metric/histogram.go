@@ -0,0 +1,136 @@
+package metric
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// windowSubdivisions is the number of buckets a window is split into by the windowed metrics, thus values expire
+	// in steps of window/windowSubdivisions
+	windowSubdivisions = 6
+	// histogramMaxSamples is the maximum number of values a WindowedHistogram keeps per bucket. Values are then
+	// reservoir sampled, making percentiles approximate.
+	histogramMaxSamples = 1024
+)
+
+// WindowedHistogram observes values over a sliding time window, e.g. the latencies of the last minute, allowing to
+// query their percentiles. Percentiles are exact as long as less than 1024 values are observed per 1/6th of the
+// window, while they are computed over a uniform sample of the values otherwise.
+type WindowedHistogram interface {
+	// Observe adds a value to the histogram.
+	Observe(float64)
+	// Count gets the number of values observed during the window.
+	Count() int64
+	// Mean gets the mean of the values observed during the window, or 0 if there are none.
+	Mean() float64
+	// Percentile gets the p-th percentile (0 <= p <= 100) of the values observed during the window, or 0 if there
+	// are none. Percentiles falling between two values are linearly interpolated.
+	Percentile(p float64) float64
+	// Percentiles is like Percentile but for several percentiles at once, which is cheaper than calling
+	// Percentile for each of them.
+	Percentiles(ps ...float64) []float64
+	// Window gets the duration of the window.
+	Window() time.Duration
+}
+
+// NewWindowedHistogram creates a new histogram over a sliding window of the given duration
+func NewWindowedHistogram(window time.Duration) WindowedHistogram {
+	return newWindowedHistogram(window, time.Now)
+}
+
+func newWindowedHistogram(window time.Duration, now func() time.Time) *windowedHistogram {
+	return &windowedHistogram{
+		duration: window,
+		window: newSlidingWindow(max(window/windowSubdivisions, 1), windowSubdivisions,
+			func(b *histogramBucket) {
+				b.mu.Lock()
+				defer b.mu.Unlock()
+				b.values, b.count, b.sum = b.values[:0], 0, 0
+			}, now,
+		),
+	}
+}
+
+type windowedHistogram struct {
+	duration time.Duration
+	window   *slidingWindow[histogramBucket]
+}
+
+type histogramBucket struct {
+	mu     sync.Mutex
+	values []float64
+	count  int64
+	sum    float64
+}
+
+func (h *windowedHistogram) Observe(v float64) {
+	b, ok := h.window.current()
+	if !ok {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.count++
+	b.sum += v
+	if len(b.values) < histogramMaxSamples {
+		b.values = append(b.values, v)
+	} else if i := rand.Int64N(b.count); i < histogramMaxSamples {
+		b.values[i] = v
+	}
+}
+
+func (h *windowedHistogram) Count() int64 {
+	var count int64
+	h.each(func(b *histogramBucket) { count += b.count })
+	return count
+}
+
+func (h *windowedHistogram) Mean() float64 {
+	var (
+		count int64
+		sum   float64
+	)
+	h.each(func(b *histogramBucket) { count, sum = count+b.count, sum+b.sum })
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+func (h *windowedHistogram) Percentile(p float64) float64 {
+	return h.Percentiles(p)[0]
+}
+
+func (h *windowedHistogram) Percentiles(ps ...float64) []float64 {
+	var values []float64
+	h.each(func(b *histogramBucket) { values = append(values, b.values...) })
+	sort.Float64s(values)
+
+	res := make([]float64, len(ps))
+	if len(values) == 0 {
+		return res
+	}
+	for i, p := range ps {
+		rank := min(max(p, 0), 100) / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := min(lower+1, len(values)-1)
+		res[i] = values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+	}
+	return res
+}
+
+func (h *windowedHistogram) Window() time.Duration {
+	return h.duration
+}
+
+func (h *windowedHistogram) each(f func(*histogramBucket)) {
+	h.window.each(windowSubdivisions, func(b *histogramBucket) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		f(b)
+	})
+}
@@ -0,0 +1,82 @@
+package metric
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// meterResolution is the granularity of the time slots in which a Meter counts events
+	meterResolution = 5 * time.Second
+	// meterMaxWindow is the longest window a Meter can compute rates over
+	meterMaxWindow = 15 * time.Minute
+)
+
+// Meter measures the rate of events, i.e. events per second, over sliding windows of up to 15 minutes.
+// Events are counted in time slots of 5 seconds, thus rates are computed over the slots overlapping the window.
+type Meter interface {
+	// Mark records the occurrence of n events.
+	Mark(n int64)
+	// Count gets the total number of events recorded.
+	Count() int64
+	// Rate gets the rate of events over the given sliding window, which is capped to 15 minutes.
+	Rate(window time.Duration) float64
+	// Rate1 gets the rate of events over the last minute.
+	Rate1() float64
+	// Rate5 gets the rate of events over the last 5 minutes.
+	Rate5() float64
+	// Rate15 gets the rate of events over the last 15 minutes.
+	Rate15() float64
+}
+
+// NewMeter creates a new meter
+func NewMeter() Meter {
+	return newMeter(time.Now)
+}
+
+func newMeter(now func() time.Time) *meter {
+	return &meter{
+		window: newSlidingWindow(meterResolution, int(meterMaxWindow/meterResolution),
+			func(count *atomic.Int64) { count.Store(0) }, now,
+		),
+	}
+}
+
+type meter struct {
+	count  atomic.Int64
+	window *slidingWindow[atomic.Int64]
+}
+
+func (m *meter) Mark(n int64) {
+	m.count.Add(n)
+	if c, ok := m.window.current(); ok {
+		c.Add(n)
+	}
+}
+
+func (m *meter) Count() int64 {
+	return m.count.Load()
+}
+
+func (m *meter) Rate(window time.Duration) float64 {
+	var count int64
+	slots := int(math.Ceil(float64(window) / float64(meterResolution)))
+	covered := m.window.each(slots, func(c *atomic.Int64) {
+		count += c.Load()
+	})
+	// avoid overestimating the rate of meters created less than a second ago
+	return float64(count) / max(covered, time.Second).Seconds()
+}
+
+func (m *meter) Rate1() float64 {
+	return m.Rate(time.Minute)
+}
+
+func (m *meter) Rate5() float64 {
+	return m.Rate(5 * time.Minute)
+}
+
+func (m *meter) Rate15() float64 {
+	return m.Rate(15 * time.Minute)
+}
@@ -0,0 +1,92 @@
+package metric
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// MinMax tracks the minimum and maximum of the values observed over a sliding time window, e.g. the smallest and
+// biggest batch sizes of the last 5 minutes. Values expire in steps of 1/6th of the window.
+type MinMax interface {
+	// Observe records a value.
+	Observe(float64)
+	// Min gets the minimum of the values observed during the window, or 0 if there are none.
+	Min() float64
+	// Max gets the maximum of the values observed during the window, or 0 if there are none.
+	Max() float64
+	// Window gets the duration of the window.
+	Window() time.Duration
+}
+
+// NewMinMax creates a new min/max tracker over a sliding window of the given duration
+func NewMinMax(window time.Duration) MinMax {
+	return newMinMax(window, time.Now)
+}
+
+func newMinMax(window time.Duration, now func() time.Time) *minMax {
+	return &minMax{
+		duration: window,
+		window: newSlidingWindow(max(window/windowSubdivisions, 1), windowSubdivisions,
+			func(b *minMaxBucket) {
+				b.minBits.Store(math.Float64bits(math.Inf(1)))
+				b.maxBits.Store(math.Float64bits(math.Inf(-1)))
+			}, now,
+		),
+	}
+}
+
+type minMax struct {
+	duration time.Duration
+	window   *slidingWindow[minMaxBucket]
+}
+
+type minMaxBucket struct {
+	minBits atomic.Uint64
+	maxBits atomic.Uint64
+}
+
+func (m *minMax) Observe(v float64) {
+	b, ok := m.window.current()
+	if !ok {
+		return
+	}
+	for {
+		oldBits := b.minBits.Load()
+		if v >= math.Float64frombits(oldBits) || b.minBits.CompareAndSwap(oldBits, math.Float64bits(v)) {
+			break
+		}
+	}
+	for {
+		oldBits := b.maxBits.Load()
+		if v <= math.Float64frombits(oldBits) || b.maxBits.CompareAndSwap(oldBits, math.Float64bits(v)) {
+			break
+		}
+	}
+}
+
+func (m *minMax) Min() float64 {
+	res := math.Inf(1)
+	m.window.each(windowSubdivisions, func(b *minMaxBucket) {
+		res = math.Min(res, math.Float64frombits(b.minBits.Load()))
+	})
+	if math.IsInf(res, 0) {
+		return 0
+	}
+	return res
+}
+
+func (m *minMax) Max() float64 {
+	res := math.Inf(-1)
+	m.window.each(windowSubdivisions, func(b *minMaxBucket) {
+		res = math.Max(res, math.Float64frombits(b.maxBits.Load()))
+	})
+	if math.IsInf(res, 0) {
+		return 0
+	}
+	return res
+}
+
+func (m *minMax) Window() time.Duration {
+	return m.duration
+}
@@ -0,0 +1,215 @@
+package metric
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelMetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+)
+
+// RegisterOTelInstruments registers an observable instrument per measurement name of the registry, so that the
+// values of the registry are read by the meter at collection time instead of being polled, e.g.
+//
+//	registration, err := metric.RegisterOTelInstruments(metric.Instance.GetRegistry(metric.PublishedMetrics), meter)
+//	...
+//	defer registration.Unregister()
+//
+// Counters are registered as Float64ObservableCounter, while gauges and moving averages are registered as
+// Float64ObservableGauge. The instrument type of a name is determined by the first measurement seen with that name,
+// and measurements of a different type with the same name are ignored. Names of measurements which can't be read as
+// a single value, e.g. meters, windowed histograms and min/max trackers, are not registered.
+// Instruments for measurements added to the registry afterwards are registered in the background, not to slow down the
+// goroutine adding them, thus they might miss the collections happening right after they are added.
+// Only registries created with NewRegistry are supported.
+func RegisterOTelInstruments(reg Registry, meter otelMetric.Meter, opts ...ExporterOption) (otelMetric.Registration, error) {
+	r, ok := reg.(*registry)
+	if !ok {
+		return nil, errors.New("registry does not support observable instruments")
+	}
+	e := &otelExporter{
+		registry:    r,
+		meter:       meter,
+		config:      newExporterConfig(opts),
+		instruments: make(map[string]struct{}),
+	}
+
+	e.removeListener = r.onNewName(e.enqueue)
+	var names []string
+	r.nameIndex.Range(func(key, _ interface{}) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	if err := e.addInstruments(names); err != nil {
+		_ = e.Unregister()
+		return nil, err
+	}
+	return e, nil
+}
+
+type otelInstrument struct {
+	name       string
+	observable otelMetric.Float64Observable
+	counter    bool
+}
+
+type otelExporter struct {
+	embedded.Registration
+
+	registry        *registry
+	meter           otelMetric.Meter
+	config          exporterConfig
+	removeListener  func()
+	lastDeleteStale atomic.Int64 // unix nanoseconds
+
+	pendingMu sync.Mutex
+	pending   []string // names waiting for their instruments to be registered
+	draining  bool     // whether a goroutine is registering the pending names
+
+	mu            sync.Mutex
+	stopped       bool
+	instruments   map[string]struct{}
+	registrations []otelMetric.Registration
+}
+
+// enqueue schedules the registration of the instrument of a new name, starting a goroutine registering the pending
+// names if none is running
+func (e *otelExporter) enqueue(name string) {
+	e.pendingMu.Lock()
+	defer e.pendingMu.Unlock()
+	e.pending = append(e.pending, name)
+	if !e.draining {
+		e.draining = true
+		go e.drain()
+	}
+}
+
+func (e *otelExporter) drain() {
+	for {
+		e.pendingMu.Lock()
+		names := e.pending
+		e.pending = nil
+		if len(names) == 0 {
+			e.draining = false
+			e.pendingMu.Unlock()
+			return
+		}
+		e.pendingMu.Unlock()
+		if err := e.addInstruments(names); err != nil {
+			otel.Handle(err)
+		}
+	}
+}
+
+// addInstruments creates the instruments of the given names, if not already existing, and registers a callback
+// observing them. Each batch of names gets its own callback, so that the callbacks of the previous batches don't
+// have to be replaced.
+func (e *otelExporter) addInstruments(names []string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return nil
+	}
+
+	var (
+		instruments []otelInstrument
+		observables []otelMetric.Observable
+	)
+	for _, name := range names {
+		if _, ok := e.instruments[name]; ok {
+			continue
+		}
+		values := e.registry.GetMetricsByName(name)
+		if len(values) == 0 {
+			continue // deleted meanwhile, the name is enqueued again once a measurement is added back
+		}
+		if _, ok := valueOf(values[0].Value); !ok {
+			e.instruments[name] = struct{}{} // never observed
+			continue
+		}
+		instrument := otelInstrument{name: name, counter: isCounter(values[0].Value)}
+		var err error
+		if instrument.counter {
+			instrument.observable, err = e.meter.Float64ObservableCounter(name)
+		} else {
+			instrument.observable, err = e.meter.Float64ObservableGauge(name)
+		}
+		if err != nil {
+			return err
+		}
+		instruments = append(instruments, instrument)
+		observables = append(observables, instrument.observable)
+	}
+	if len(instruments) == 0 {
+		return nil
+	}
+
+	registration, err := e.meter.RegisterCallback(func(_ context.Context, o otelMetric.Observer) error {
+		e.observe(o, instruments)
+		return nil
+	}, observables...)
+	if err != nil {
+		return err
+	}
+	for _, instrument := range instruments {
+		e.instruments[instrument.name] = struct{}{}
+	}
+	e.registrations = append(e.registrations, registration)
+	return nil
+}
+
+func (e *otelExporter) observe(o otelMetric.Observer, instruments []otelInstrument) {
+	e.deleteStale()
+	for _, instrument := range instruments {
+		for _, value := range e.registry.GetMetricsByName(instrument.name) {
+			if isCounter(value.Value) != instrument.counter {
+				continue
+			}
+			v, ok := valueOf(value.Value)
+			if !ok {
+				continue
+			}
+			attrs := make([]attribute.KeyValue, 0, len(value.Tags))
+			for k, v := range value.Tags {
+				attrs = append(attrs, attribute.String(k, v))
+			}
+			o.ObserveFloat64(instrument.observable, v, otelMetric.WithAttributes(attrs...))
+		}
+	}
+}
+
+// deleteStale deletes the stale measurements at most once per second, i.e. once per collection even though every
+// batch of instruments is observed by its own callback
+func (e *otelExporter) deleteStale() {
+	if e.config.ttl <= 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := e.lastDeleteStale.Load()
+	if now-last < int64(time.Second) || !e.lastDeleteStale.CompareAndSwap(last, now) {
+		return
+	}
+	e.registry.DeleteStale(e.config.ttl)
+}
+
+// Unregister stops observing the registry
+func (e *otelExporter) Unregister() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return nil
+	}
+	e.stopped = true
+	e.removeListener()
+	var errs []error
+	for _, registration := range e.registrations {
+		errs = append(errs, registration.Unregister())
+	}
+	e.registrations = nil
+	return errors.Join(errs...)
+}
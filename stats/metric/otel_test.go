@@ -0,0 +1,98 @@
+package metric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRegisterOTelInstruments(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = meterProvider.Shutdown(context.Background()) })
+
+	collect := func(t *testing.T) map[string]metricdata.Aggregation {
+		t.Helper()
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		metrics := make(map[string]metricdata.Aggregation)
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				metrics[m.Name] = m.Data
+			}
+		}
+		return metrics
+	}
+
+	registry := NewRegistry()
+	registry.MustGetCounter(testMeasurement{name: "processed", tag: "a"}).Add(3)
+	registry.MustGetMeter(testMeasurement{name: "events", tag: "a"}).Mark(1)
+
+	registration, err := RegisterOTelInstruments(registry, meterProvider.Meter(t.Name()))
+	require.NoError(t, err)
+
+	// measurements added after the registration are observed as well
+	registry.MustGetGauge(testMeasurement{name: "queue_size", tag: "a"}).Set(10)
+	registry.MustGetCounter(testMeasurement{name: "processed", tag: "b"}).Inc()
+	registry.MustGetSimpleMovingAvg(testMeasurement{name: "latency", tag: "a"}).Set(0.5)
+	registry.MustGetMinMax(testMeasurement{name: "size", tag: "a"}, time.Minute).Observe(1)
+
+	// instruments of new names are registered in the background
+	var metrics map[string]metricdata.Aggregation
+	require.Eventually(t, func() bool {
+		metrics = collect(t)
+		return len(metrics) == 3
+	}, time.Second, time.Millisecond)
+	require.NotContains(t, metrics, "events", "meters can't be observed")
+	require.NotContains(t, metrics, "size", "min/max trackers can't be observed")
+	processed, ok := metrics["processed"].(metricdata.Sum[float64])
+	require.True(t, ok, "counters should be exported as sums")
+	require.True(t, processed.IsMonotonic)
+	require.ElementsMatch(t, []float64{3, 1}, []float64{processed.DataPoints[0].Value, processed.DataPoints[1].Value})
+	queueSize, ok := metrics["queue_size"].(metricdata.Gauge[float64])
+	require.True(t, ok, "gauges should be exported as gauges")
+	require.Len(t, queueSize.DataPoints, 1)
+	require.Equal(t, 10.0, queueSize.DataPoints[0].Value)
+	require.Equal(t, attribute.NewSet(attribute.String("tag", "a")), queueSize.DataPoints[0].Attributes)
+	latency, ok := metrics["latency"].(metricdata.Gauge[float64])
+	require.True(t, ok, "moving averages should be exported as gauges")
+	require.Equal(t, 0.5, latency.DataPoints[0].Value)
+
+	// values are read at collection time
+	registry.MustGetGauge(testMeasurement{name: "queue_size", tag: "a"}).Set(5)
+	registry.Delete(testMeasurement{name: "processed", tag: "a"})
+	metrics = collect(t)
+	require.Equal(t, 5.0, metrics["queue_size"].(metricdata.Gauge[float64]).DataPoints[0].Value)
+	require.Len(t, metrics["processed"].(metricdata.Sum[float64]).DataPoints, 1)
+
+	// measurements deleted and added back are observed again
+	registry.Delete(testMeasurement{name: "latency", tag: "a"})
+	registry.MustGetSimpleMovingAvg(testMeasurement{name: "latency", tag: "b"}).Set(1)
+	require.Equal(t, 1.0, collect(t)["latency"].(metricdata.Gauge[float64]).DataPoints[0].Value)
+
+	require.NoError(t, registration.Unregister())
+	require.NoError(t, registration.Unregister(), "unregister should be idempotent")
+	require.Zero(t, countNameListeners(registry), "unregister should stop listening to new names")
+	registry.MustGetGauge(testMeasurement{name: "new_gauge"}).Set(1)
+	require.NotContains(t, collect(t), "new_gauge")
+}
+
+func countNameListeners(r Registry) int {
+	reg := r.(*registry)
+	reg.listenersMu.RLock()
+	defer reg.listenersMu.RUnlock()
+	return len(reg.listeners)
+}
+
+type customRegistry struct{ Registry }
+
+func TestRegisterOTelInstrumentsUnsupportedRegistry(t *testing.T) {
+	meterProvider := sdkmetric.NewMeterProvider()
+	_, err := RegisterOTelInstruments(customRegistry{NewRegistry()}, meterProvider.Meter(t.Name()))
+	require.Error(t, err)
+}
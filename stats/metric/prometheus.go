@@ -0,0 +1,86 @@
+package metric
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewPrometheusCollector returns a prometheus.Collector reading the values of the registry at scrape time, e.g.
+//
+//	prometheus.MustRegister(metric.NewPrometheusCollector(metric.Instance.GetRegistry(metric.PublishedMetrics)))
+//
+// Counters are exported as Prometheus counters, while gauges and moving averages are exported as Prometheus gauges.
+// Names and tag keys are sanitized to be valid Prometheus names, i.e. invalid characters are replaced with "_".
+// Since the registry can contain any measurement, the collector is unchecked and measurements with the same name
+// are expected to have the same tag keys.
+func NewPrometheusCollector(registry Registry, opts ...ExporterOption) prometheus.Collector {
+	return &prometheusCollector{registry: registry, config: newExporterConfig(opts)}
+}
+
+type prometheusCollector struct {
+	registry Registry
+	config   exporterConfig
+}
+
+// Describe sends no descriptors, making the collector unchecked
+func (c *prometheusCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c *prometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.config.ttl > 0 {
+		c.registry.DeleteStale(c.config.ttl)
+	}
+	c.registry.Range(func(key, value interface{}) bool {
+		m := key.(Measurement)
+		valueType := prometheus.GaugeValue
+		if isCounter(value) {
+			valueType = prometheus.CounterValue
+		}
+		v, ok := valueOf(value)
+		if !ok {
+			return true
+		}
+
+		tags := m.GetTags()
+		labelNames := make([]string, 0, len(tags))
+		for k := range tags {
+			labelNames = append(labelNames, k)
+		}
+		sort.Strings(labelNames)
+		labelValues := make([]string, len(labelNames))
+		for i, k := range labelNames {
+			labelValues[i] = tags[k]
+			labelNames[i] = sanitizePrometheusName(k, false)
+		}
+
+		desc := prometheus.NewDesc(sanitizePrometheusName(m.GetName(), true), m.GetName(), labelNames, nil)
+		metric, err := prometheus.NewConstMetric(desc, valueType, v, labelValues...)
+		if err != nil {
+			metric = prometheus.NewInvalidMetric(desc, err)
+		}
+		ch <- metric
+		return true
+	})
+}
+
+// sanitizePrometheusName replaces the characters not allowed in Prometheus metric (colons included) or label names
+// with "_", prefixing the name with "_" if it starts with a digit
+func sanitizePrometheusName(name string, allowColons bool) string {
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (allowColons && r == ':'):
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
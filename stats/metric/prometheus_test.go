@@ -0,0 +1,74 @@
+package metric
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	registry := NewRegistry()
+	promRegistry := prometheus.NewRegistry()
+	promRegistry.MustRegister(NewPrometheusCollector(registry))
+
+	registry.MustGetCounter(testMeasurement{name: "jobs.processed", tag: "a"}).Add(3)
+	registry.MustGetCounter(testMeasurement{name: "jobs.processed", tag: "b"}).Inc()
+	registry.MustGetGauge(testMeasurement{name: "queue-size", tag: "a"}).Set(10)
+	registry.MustGetVarMovingAvg(testMeasurement{name: "1m_latency", tag: "a"}, 5).Set(0.5)
+
+	require.NoError(t, testutil.GatherAndCompare(promRegistry, strings.NewReader(`
+# HELP _1m_latency 1m_latency
+# TYPE _1m_latency gauge
+_1m_latency{tag="a"} 0.5
+# HELP jobs_processed jobs.processed
+# TYPE jobs_processed counter
+jobs_processed{tag="a"} 3
+jobs_processed{tag="b"} 1
+# HELP queue_size queue-size
+# TYPE queue_size gauge
+queue_size{tag="a"} 10
+`)))
+
+	// values are read at scrape time
+	registry.MustGetGauge(testMeasurement{name: "queue-size", tag: "a"}).Set(5)
+	require.NoError(t, testutil.GatherAndCompare(promRegistry, strings.NewReader(`
+# HELP queue_size queue-size
+# TYPE queue_size gauge
+queue_size{tag="a"} 5
+`), "queue_size"))
+
+	registry.Delete(testMeasurement{name: "jobs.processed", tag: "a"})
+	require.NoError(t, testutil.GatherAndCompare(promRegistry, strings.NewReader(`
+# HELP jobs_processed jobs.processed
+# TYPE jobs_processed counter
+jobs_processed{tag="b"} 1
+`), "jobs_processed"))
+}
+
+func TestPrometheusCollectorStaleTTL(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry()
+	r.(*registry).now = func() time.Time { return now }
+	promRegistry := prometheus.NewRegistry()
+	promRegistry.MustRegister(NewPrometheusCollector(r, WithStaleTTL(time.Minute)))
+
+	gauge := r.MustGetGauge(testMeasurement{name: "active", tag: "a"})
+	gauge.Set(1)
+	r.MustGetGauge(testMeasurement{name: "stale", tag: "a"}).Set(1)
+	require.Equal(t, 2, testutil.CollectAndCount(promRegistry))
+
+	now = now.Add(time.Minute)
+	gauge.Set(2)
+	require.Equal(t, 1, testutil.CollectAndCount(promRegistry), "only the changed gauge should be exported")
+}
+
+func TestSanitizePrometheusName(t *testing.T) {
+	require.Equal(t, "jobs_processed_total", sanitizePrometheusName("jobs.processed-total", true))
+	require.Equal(t, "_1m:rate", sanitizePrometheusName("1m:rate", true))
+	require.Equal(t, "_1m_rate", sanitizePrometheusName("1m:rate", false))
+	require.Equal(t, "workspaceId", sanitizePrometheusName("workspaceId", false))
+}
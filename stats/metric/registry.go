@@ -2,7 +2,10 @@ package metric
 
 import (
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type (
@@ -67,11 +70,70 @@ type Registry interface {
 	// e.g. a Counter, it panics
 	MustGetVarMovingAvg(m Measurement, age float64) MovingAverage
 
+	// GetMeter gets a meter by key. If a value for this key
+	// already exists but corresponds to another measurement
+	// type, e.g. a Counter, an error is returned
+	GetMeter(Measurement) (Meter, error)
+
+	// MustGetMeter gets a meter by key. If a value for this key
+	// already exists but corresponds to another measurement type,
+	// e.g. a Counter, it panics
+	MustGetMeter(Measurement) Meter
+
+	// GetWindowedHistogram gets a windowed histogram by key. If a value for
+	// this key already exists but corresponds to another measurement type,
+	// e.g. a Counter, or to a histogram with a different window, an error
+	// is returned
+	GetWindowedHistogram(m Measurement, window time.Duration) (WindowedHistogram, error)
+
+	// MustGetWindowedHistogram gets a windowed histogram by key. If a value
+	// for this key already exists but corresponds to another measurement
+	// type, e.g. a Counter, or to a histogram with a different window, it
+	// panics
+	MustGetWindowedHistogram(m Measurement, window time.Duration) WindowedHistogram
+
+	// GetMinMax gets a min/max tracker by key. If a value for this key
+	// already exists but corresponds to another measurement type, e.g. a
+	// Counter, or to a tracker with a different window, an error is
+	// returned
+	GetMinMax(m Measurement, window time.Duration) (MinMax, error)
+
+	// MustGetMinMax gets a min/max tracker by key. If a value for this key
+	// already exists but corresponds to another measurement type, e.g. a
+	// Counter, or to a tracker with a different window, it panics
+	MustGetMinMax(m Measurement, window time.Duration) MinMax
+
 	// Range scans across all metrics
 	Range(f func(key, value interface{}) bool)
 
 	// GetMetricsByName gets all metrics with this name
 	GetMetricsByName(name string) []TagsWithValue
+
+	// Delete removes a measurement from the registry. Further updates to
+	// an instance retrieved before its deletion are not reported anymore,
+	// while a new instance is created the next time the measurement is
+	// retrieved.
+	Delete(Measurement)
+
+	// DeleteStale removes all measurements which have neither been
+	// retrieved nor changed their value during the given ttl, returning
+	// the number of measurements removed. Changes are detected by
+	// comparing the values observed by consecutive calls, thus it is
+	// meant to be called periodically, e.g. by an exporter.
+	// Meters, windowed histograms and min/max trackers are meant for
+	// in-process decisions only: they are neither exported nor removed
+	// by DeleteStale.
+	DeleteStale(ttl time.Duration) int
+}
+
+// activity keeps track of the last time a measurement was retrieved or
+// changed its value, for detecting stale measurements
+type activity struct {
+	retrieved atomic.Int64 // unix nanoseconds
+
+	// the following fields are protected by registry.staleMu
+	value   float64
+	changed int64 // unix nanoseconds
 }
 
 // mutexWithMap bundles a lock along with the map it is protecting
@@ -104,6 +166,7 @@ func NewRegistry() Registry {
 		simpleEwmas: sync.Pool{New: simpleEwmaGenerator},
 		varEwmas:    sync.Pool{New: varEwmaGenerator},
 		sets:        sync.Pool{New: indexGenerator},
+		now:         time.Now,
 	}
 }
 
@@ -115,6 +178,18 @@ type registry struct {
 	simpleEwmas sync.Pool
 	varEwmas    sync.Pool
 	sets        sync.Pool
+
+	activities      sync.Map    // Measurement -> *activity
+	trackRetrievals atomic.Bool // set by the first call to DeleteStale, retrievals being recorded only if needed
+	staleMu         sync.Mutex
+	now             func() time.Time // To mock out time.Now() for testing.
+
+	listenersMu sync.RWMutex
+	listeners   []*nameListener
+}
+
+type nameListener struct {
+	f func(name string)
 }
 
 func (r *registry) GetCounter(m Measurement) (Counter, error) {
@@ -181,6 +256,7 @@ func (r *registry) GetVarMovingAvg(m Measurement, age float64) (MovingAverage, e
 			r.updateIndex(m, res)
 		}
 	}
+	r.touch(m)
 	ma, ok := res.(*VariableEWMA)
 	if !ok {
 		return nil, fmt.Errorf("a different type of metric exists in the registry with the same key [%+v]: %T", m, res)
@@ -200,6 +276,69 @@ func (r *registry) MustGetVarMovingAvg(m Measurement, age float64) MovingAverage
 	return ma
 }
 
+func (r *registry) GetMeter(m Measurement) (Meter, error) {
+	res := r.getOrStore(m, func() interface{} { return NewMeter() })
+	meter, ok := res.(Meter)
+	if !ok {
+		return nil, fmt.Errorf("a different type of metric exists in the registry with the same key [%+v]: %T", m, res)
+	}
+	return meter, nil
+}
+
+func (r *registry) MustGetMeter(m Measurement) Meter {
+	meter, err := r.GetMeter(m)
+	if err != nil {
+		panic(err)
+	}
+	return meter
+}
+
+func (r *registry) GetWindowedHistogram(m Measurement, window time.Duration) (WindowedHistogram, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid window %s for windowed histogram [%+v]", window, m)
+	}
+	res := r.getOrStore(m, func() interface{} { return NewWindowedHistogram(window) })
+	h, ok := res.(WindowedHistogram)
+	if !ok {
+		return nil, fmt.Errorf("a different type of metric exists in the registry with the same key [%+v]: %T", m, res)
+	}
+	if h.Window() != window {
+		return nil, fmt.Errorf("another windowed histogram with window %s instead of %s exists in the registry with the same key [%+v]: %T", h.Window(), window, m, res)
+	}
+	return h, nil
+}
+
+func (r *registry) MustGetWindowedHistogram(m Measurement, window time.Duration) WindowedHistogram {
+	h, err := r.GetWindowedHistogram(m, window)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func (r *registry) GetMinMax(m Measurement, window time.Duration) (MinMax, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid window %s for min/max tracker [%+v]", window, m)
+	}
+	res := r.getOrStore(m, func() interface{} { return NewMinMax(window) })
+	mm, ok := res.(MinMax)
+	if !ok {
+		return nil, fmt.Errorf("a different type of metric exists in the registry with the same key [%+v]: %T", m, res)
+	}
+	if mm.Window() != window {
+		return nil, fmt.Errorf("another min/max tracker with window %s instead of %s exists in the registry with the same key [%+v]: %T", mm.Window(), window, m, res)
+	}
+	return mm, nil
+}
+
+func (r *registry) MustGetMinMax(m Measurement, window time.Duration) MinMax {
+	mm, err := r.GetMinMax(m, window)
+	if err != nil {
+		panic(err)
+	}
+	return mm
+}
+
 func (r *registry) Range(f func(key, value interface{}) bool) {
 	r.store.Range(f)
 }
@@ -220,6 +359,75 @@ func (r *registry) GetMetricsByName(name string) []TagsWithValue {
 	return values
 }
 
+func (r *registry) Delete(m Measurement) {
+	if _, ok := r.store.LoadAndDelete(m); !ok {
+		return
+	}
+	r.activities.Delete(m)
+	if metricsSet, ok := r.nameIndex.Load(m.GetName()); ok {
+		lock := metricsSet.(*mutexWithMap).lock
+		lock.Lock()
+		delete(metricsSet.(*mutexWithMap).value, m)
+		lock.Unlock()
+	}
+}
+
+func (r *registry) DeleteStale(ttl time.Duration) int {
+	r.staleMu.Lock()
+	defer r.staleMu.Unlock()
+	r.trackRetrievals.Store(true)
+
+	now := r.now().UnixNano()
+	var stale []Measurement
+	r.store.Range(func(key, value interface{}) bool {
+		m := key.(Measurement)
+		v, ok := valueOf(value)
+		if !ok {
+			return true
+		}
+		res, loaded := r.activities.LoadOrStore(m, &activity{value: v, changed: now})
+		a := res.(*activity)
+		if !loaded {
+			return true
+		}
+		if a.value != v {
+			a.value, a.changed = v, now
+			return true
+		}
+		if lastActive := max(a.changed, a.retrieved.Load()); now-lastActive >= ttl.Nanoseconds() {
+			stale = append(stale, m)
+		}
+		return true
+	})
+	for _, m := range stale {
+		r.Delete(m)
+	}
+	// drop the activities of the measurements deleted concurrently
+	r.activities.Range(func(key, _ interface{}) bool {
+		if _, ok := r.store.Load(key); !ok {
+			r.activities.Delete(key)
+		}
+		return true
+	})
+	return len(stale)
+}
+
+// onNewName registers a function called whenever a measurement is added to the registry
+// while no other measurement has its name, returning a function removing it.
+// The function is called by the goroutine adding the measurement, thus it should return quickly.
+func (r *registry) onNewName(f func(name string)) (remove func()) {
+	l := &nameListener{f: f}
+	r.listenersMu.Lock()
+	defer r.listenersMu.Unlock()
+	r.listeners = append(r.listeners, l)
+	return func() {
+		r.listenersMu.Lock()
+		defer r.listenersMu.Unlock()
+		// the listeners are copied since updateIndex iterates them without holding the lock
+		r.listeners = slices.DeleteFunc(slices.Clone(r.listeners), func(other *nameListener) bool { return other == l })
+	}
+}
+
 func (r *registry) updateIndex(m Measurement, metric interface{}) {
 	name := m.GetName()
 	newSet := r.sets.Get()
@@ -230,8 +438,53 @@ func (r *registry) updateIndex(m Measurement, metric interface{}) {
 
 	lock := res.(*mutexWithMap).lock
 	lock.Lock()
+	first := len(res.(*mutexWithMap).value) == 0
 	res.(*mutexWithMap).value[m] = TagsWithValue{m.GetTags(), metric}
 	lock.Unlock()
+
+	if first {
+		r.listenersMu.RLock()
+		listeners := r.listeners
+		r.listenersMu.RUnlock()
+		for _, l := range listeners {
+			l.f(name)
+		}
+	}
+}
+
+// touch records that a measurement has been retrieved, if stale measurements are deleted.
+// Activities are only created by DeleteStale: measurements retrieved since its last call are not stale anyway.
+func (r *registry) touch(m Measurement) {
+	if !r.trackRetrievals.Load() {
+		return
+	}
+	if res, ok := r.activities.Load(m); ok {
+		res.(*activity).retrieved.Store(r.now().UnixNano())
+	}
+}
+
+// isCounter returns true if the metric is a counter. Gauges satisfy the Counter interface as well, thus a type
+// assertion is not enough.
+func isCounter(metric interface{}) bool {
+	if _, ok := metric.(Gauge); ok {
+		return false
+	}
+	_, ok := metric.(Counter)
+	return ok
+}
+
+// valueOf returns the current value of a metric stored in the registry
+func valueOf(metric interface{}) (float64, bool) {
+	switch metric := metric.(type) {
+	case Gauge:
+		return metric.Value(), true
+	case Counter:
+		return metric.Value(), true
+	case MovingAverage:
+		return metric.Value(), true
+	default:
+		return 0, false
+	}
 }
 
 func (r *registry) get(m Measurement, pool *sync.Pool) interface{} {
@@ -245,5 +498,20 @@ func (r *registry) get(m Measurement, pool *sync.Pool) interface{} {
 			r.updateIndex(m, res)
 		}
 	}
+	r.touch(m)
+	return res
+}
+
+// getOrStore is like get, for measurements which can't be pooled since they
+// depend on parameters, e.g. a window, or on their creation time, e.g. meters
+func (r *registry) getOrStore(m Measurement, newValue func() interface{}) interface{} {
+	res, ok := r.store.Load(m)
+	if !ok {
+		res, ok = r.store.LoadOrStore(m, newValue())
+		if !ok {
+			r.updateIndex(m, res)
+		}
+	}
+	r.touch(m)
 	return res
 }
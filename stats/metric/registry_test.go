@@ -4,6 +4,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -207,3 +208,84 @@ func benchmarkMutexMapGetIntAndInc(b *testing.B, concurrency int) {
 	start.Done()
 	end.Wait()
 }
+
+func TestRegistryDelete(t *testing.T) {
+	registry := NewRegistry()
+
+	key := testMeasurement{name: "key", tag: "a"}
+	counter := registry.MustGetCounter(key)
+	counter.Inc()
+	registry.MustGetCounter(testMeasurement{name: "key", tag: "b"}).Inc()
+
+	registry.Delete(key)
+	registry.Delete(testMeasurement{name: "missing"}) // no-op
+	require.Len(t, registry.GetMetricsByName("key"), 1)
+	var keys []Measurement
+	registry.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key.(Measurement))
+		return true
+	})
+	require.Equal(t, []Measurement{testMeasurement{name: "key", tag: "b"}}, keys)
+
+	// a new counter is created after a deletion
+	require.Zero(t, registry.MustGetCounter(key).Value())
+	require.Len(t, registry.GetMetricsByName("key"), 2)
+
+	// the same key can then be used for another type of measurement
+	registry.Delete(key)
+	require.NotPanics(t, func() { registry.MustGetGauge(key).Set(1) })
+}
+
+func TestRegistryActivities(t *testing.T) {
+	r := NewRegistry().(*registry)
+	activities := func() (n int) {
+		r.activities.Range(func(_, _ interface{}) bool { n++; return true })
+		return n
+	}
+
+	key := testMeasurement{name: "key"}
+	r.MustGetCounter(key).Inc()
+	require.Zero(t, activities(), "retrievals aren't tracked unless stale measurements are deleted")
+
+	require.Zero(t, r.DeleteStale(time.Minute))
+	require.Equal(t, 1, activities())
+
+	r.Delete(key)
+	r.activities.Store(key, &activity{}) // e.g. stored by DeleteStale while the measurement was being deleted
+	r.MustGetGauge(testMeasurement{name: "other"})
+	require.Zero(t, r.DeleteStale(time.Minute))
+	require.Equal(t, 1, activities(), "the activity of the deleted measurement should be dropped")
+}
+
+func TestRegistryDeleteStale(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry()
+	r.(*registry).now = func() time.Time { return now }
+
+	unchanged := testMeasurement{name: "unchanged"}
+	changed := testMeasurement{name: "changed"}
+	retrieved := testMeasurement{name: "retrieved"}
+	r.MustGetCounter(unchanged).Inc()
+	changedGauge := r.MustGetGauge(changed)
+	changedGauge.Set(1)
+	r.MustGetSimpleMovingAvg(retrieved).Add(1)
+	require.Zero(t, r.DeleteStale(time.Minute))
+
+	now = now.Add(30 * time.Second)
+	changedGauge.Set(2)
+	require.Zero(t, r.DeleteStale(time.Minute))
+
+	now = now.Add(30 * time.Second)
+	r.MustGetSimpleMovingAvg(retrieved)
+	require.Equal(t, 1, r.DeleteStale(time.Minute), "only the unchanged counter should be stale")
+	require.Nil(t, r.GetMetricsByName("unchanged"))
+	require.Len(t, r.GetMetricsByName("changed"), 1)
+	require.Len(t, r.GetMetricsByName("retrieved"), 1)
+
+	now = now.Add(time.Minute)
+	require.Equal(t, 2, r.DeleteStale(time.Minute))
+	r.Range(func(key, _ interface{}) bool {
+		t.Errorf("Expected an empty registry, got %v", key)
+		return true
+	})
+}
@@ -0,0 +1,76 @@
+package metric
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// slidingWindow splits a sliding time window in buckets of the given resolution, which are reused in a ring as time
+// advances. Updates only take a lock when a bucket has to be rolled over to a new time slot, i.e. at most once per
+// bucket and resolution. Buckets are only ever rolled forward: an update whose time slot is older than the one of its
+// bucket, e.g. because the updating goroutine was preempted after reading the time, is dropped. Since rollovers are
+// not synchronized with the updates of the bucket values, an update racing with a rollover might still be accounted in
+// the newer time slot.
+type slidingWindow[B any] struct {
+	resolution time.Duration
+	buckets    []windowBucket[B]
+	reset      func(*B)
+	created    time.Time
+	now        func() time.Time // To mock out time.Now() for testing.
+
+	mu sync.Mutex // serializes rollovers
+}
+
+type windowBucket[B any] struct {
+	slot  atomic.Int64 // the time slot, i.e. unix nanoseconds / resolution, whose values the bucket holds
+	value B
+}
+
+func newSlidingWindow[B any](resolution time.Duration, size int, reset func(*B), now func() time.Time) *slidingWindow[B] {
+	return &slidingWindow[B]{
+		resolution: resolution,
+		buckets:    make([]windowBucket[B], size),
+		reset:      reset,
+		created:    now(),
+		now:        now,
+	}
+}
+
+// current returns the bucket of the current time slot, or false if the bucket has already been rolled over to a newer
+// time slot
+func (w *slidingWindow[B]) current() (*B, bool) {
+	slot := w.now().UnixNano() / int64(w.resolution)
+	b := &w.buckets[slot%int64(len(w.buckets))]
+	if b.slot.Load() < slot {
+		w.mu.Lock()
+		if b.slot.Load() < slot {
+			w.reset(&b.value)
+			b.slot.Store(slot)
+		}
+		w.mu.Unlock()
+	}
+	if b.slot.Load() != slot {
+		return nil, false
+	}
+	return &b.value, true
+}
+
+// each calls f for the buckets of the last n time slots, the current one included, returning the duration they
+// cover, i.e. from the start of the oldest slot (or the creation of the window, if later) until now
+func (w *slidingWindow[B]) each(n int, f func(*B)) time.Duration {
+	n = min(max(n, 1), len(w.buckets))
+	now := w.now()
+	current := now.UnixNano() / int64(w.resolution)
+	for slot := current - int64(n) + 1; slot <= current; slot++ {
+		b := &w.buckets[slot%int64(len(w.buckets))]
+		if b.slot.Load() == slot {
+			f(&b.value)
+		}
+	}
+	start := time.Unix(0, (current-int64(n)+1)*int64(w.resolution))
+	if w.created.After(start) {
+		start = w.created
+	}
+	return now.Sub(start)
+}
@@ -0,0 +1,235 @@
+package metric
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockClock is a clock for testing windowed metrics
+type mockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newMockClock() *mockClock {
+	return &mockClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestMeter(t *testing.T) {
+	clock := newMockClock()
+	m := newMeter(clock.Now)
+	require.Zero(t, m.Rate1())
+
+	// 10 events per second during 5 minutes
+	for i := 0; i < 300; i++ {
+		clock.Advance(time.Second)
+		m.Mark(10)
+	}
+	require.EqualValues(t, 3000, m.Count())
+	require.InDelta(t, 10, m.Rate1(), 0.2)
+	require.InDelta(t, 10, m.Rate5(), 0.2)
+	require.InDelta(t, 10, m.Rate15(), 0.2, "rates are computed since the creation of the meter")
+
+	// no events during 2 minutes
+	clock.Advance(2 * time.Minute)
+	require.Zero(t, m.Rate1())
+	require.InDelta(t, 6, m.Rate5(), 0.2)
+	require.InDelta(t, 3000.0/420, m.Rate15(), 0.2)
+	require.InDelta(t, 3000.0/420, m.Rate(time.Hour), 0.2, "windows are capped to 15 minutes")
+
+	// all events expire after 15 minutes
+	clock.Advance(15 * time.Minute)
+	require.Zero(t, m.Rate15())
+	require.EqualValues(t, 3000, m.Count())
+
+	t.Run("just created", func(t *testing.T) {
+		m := newMeter(clock.Now)
+		m.Mark(3)
+		require.Equal(t, 3.0, m.Rate1())
+	})
+}
+
+func TestMeterConcurrently(t *testing.T) {
+	const concurrency = 100
+	m := NewMeter()
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Mark(1)
+				_ = m.Rate1()
+			}
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, concurrency*100, m.Count())
+}
+
+func TestSlidingWindowStaleWriter(t *testing.T) {
+	clock := newMockClock()
+	staleNow := clock.Now() // the time read by a writer preempted before updating its bucket
+	w := newSlidingWindow(time.Second, 4, func(count *int) { *count = 0 }, clock.Now)
+
+	// another writer rolls the bucket of the stale slot over to a newer slot
+	clock.Advance(4 * time.Second)
+	c, ok := w.current()
+	require.True(t, ok)
+	*c = 5
+
+	// the stale writer neither resets the newer slot nor accounts its value in it
+	w.now = func() time.Time { return staleNow }
+	_, ok = w.current()
+	require.False(t, ok)
+
+	w.now = clock.Now
+	var total int
+	w.each(4, func(count *int) { total += *count })
+	require.Equal(t, 5, total)
+
+	t.Run("meter", func(t *testing.T) {
+		clock := newMockClock()
+		staleNow := clock.Now()
+		m := newMeter(clock.Now)
+		clock.Advance(meterMaxWindow)
+		m.Mark(5)
+		rate := m.Rate1()
+		require.NotZero(t, rate)
+
+		m.window.now = func() time.Time { return staleNow }
+		m.Mark(1)
+		m.window.now = clock.Now
+		require.EqualValues(t, 6, m.Count(), "the count is not windowed")
+		require.Equal(t, rate, m.Rate1())
+	})
+}
+
+func TestWindowedHistogram(t *testing.T) {
+	clock := newMockClock()
+	h := newWindowedHistogram(time.Minute, clock.Now)
+	require.Zero(t, h.Count())
+	require.Zero(t, h.Mean())
+	require.Zero(t, h.Percentile(99))
+
+	for i := 1; i <= 100; i++ {
+		h.Observe(float64(i))
+	}
+	require.EqualValues(t, 100, h.Count())
+	require.Equal(t, 50.5, h.Mean())
+	require.InDeltaSlice(t, []float64{1, 50.5, 90.1, 99.01, 100}, h.Percentiles(0, 50, 90, 99, 100), 1e-9)
+	require.Equal(t, 100.0, h.Percentile(150), "percentiles are capped to 100")
+
+	// values expire in steps of 10s
+	clock.Advance(30 * time.Second)
+	h.Observe(1000)
+	require.EqualValues(t, 101, h.Count())
+	require.Equal(t, 1000.0, h.Percentile(100))
+
+	clock.Advance(30 * time.Second)
+	require.EqualValues(t, 1, h.Count())
+	require.Equal(t, 1000.0, h.Percentile(50))
+	require.Equal(t, time.Minute, h.Window())
+
+	t.Run("sampling", func(t *testing.T) {
+		h := newWindowedHistogram(time.Minute, clock.Now)
+		for i := 0; i < 100*histogramMaxSamples; i++ {
+			h.Observe(float64(i % 100))
+		}
+		require.EqualValues(t, 100*histogramMaxSamples, h.Count())
+		require.InDelta(t, 49.5, h.Mean(), 0.001)
+		require.InDelta(t, 50, h.Percentile(50), 10)
+	})
+}
+
+func TestMinMax(t *testing.T) {
+	clock := newMockClock()
+	mm := newMinMax(5*time.Minute, clock.Now)
+	require.Zero(t, mm.Min())
+	require.Zero(t, mm.Max())
+
+	mm.Observe(5)
+	mm.Observe(-2)
+	mm.Observe(3)
+	require.Equal(t, -2.0, mm.Min())
+	require.Equal(t, 5.0, mm.Max())
+
+	clock.Advance(3 * time.Minute)
+	mm.Observe(4)
+	require.Equal(t, -2.0, mm.Min())
+	require.Equal(t, 5.0, mm.Max())
+
+	clock.Advance(3 * time.Minute)
+	require.Equal(t, 4.0, mm.Min())
+	require.Equal(t, 4.0, mm.Max())
+
+	clock.Advance(5 * time.Minute)
+	require.Zero(t, mm.Min())
+	require.Zero(t, mm.Max())
+}
+
+func TestMinMaxConcurrently(t *testing.T) {
+	const concurrency = 100
+	mm := NewMinMax(time.Minute)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func(i int) {
+			defer wg.Done()
+			mm.Observe(float64(i))
+			_ = mm.Max()
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, 0.0, mm.Min())
+	require.Equal(t, float64(concurrency-1), mm.Max())
+	require.False(t, math.IsInf(mm.Max(), 0))
+}
+
+func TestRegistryWindowedMetrics(t *testing.T) {
+	registry := NewRegistry()
+
+	meterKey := testMeasurement{name: "meter"}
+	registry.MustGetMeter(meterKey).Mark(2)
+	require.EqualValues(t, 2, registry.MustGetMeter(meterKey).Count())
+
+	histogramKey := testMeasurement{name: "histogram"}
+	registry.MustGetWindowedHistogram(histogramKey, time.Minute).Observe(2)
+	require.EqualValues(t, 1, registry.MustGetWindowedHistogram(histogramKey, time.Minute).Count())
+	_, err := registry.GetWindowedHistogram(histogramKey, time.Hour)
+	require.EqualError(t, err, "another windowed histogram with window 1m0s instead of 1h0m0s exists in the registry with the same key [{name:histogram tag:}]: *metric.windowedHistogram")
+	_, err = registry.GetWindowedHistogram(testMeasurement{name: "invalid"}, 0)
+	require.Error(t, err)
+
+	minMaxKey := testMeasurement{name: "minmax"}
+	registry.MustGetMinMax(minMaxKey, time.Minute).Observe(2)
+	require.Equal(t, 2.0, registry.MustGetMinMax(minMaxKey, time.Minute).Max())
+	_, err = registry.GetMinMax(minMaxKey, time.Hour)
+	require.Error(t, err)
+
+	_, err = registry.GetMeter(histogramKey)
+	require.EqualError(t, err, "a different type of metric exists in the registry with the same key [{name:histogram tag:}]: *metric.windowedHistogram")
+	_, err = registry.GetMinMax(meterKey, time.Minute)
+	require.Error(t, err)
+	require.Panics(t, func() { registry.MustGetWindowedHistogram(meterKey, time.Minute) })
+
+	// windowed metrics are not exported and never stale
+	require.Len(t, registry.GetMetricsByName("meter"), 1)
+	require.Zero(t, registry.DeleteStale(0))
+}
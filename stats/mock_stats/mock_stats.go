@@ -111,6 +111,20 @@ func (mr *MockStatsMockRecorder) RegisterCollector(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCollector", reflect.TypeOf((*MockStats)(nil).RegisterCollector), arg0)
 }
 
+// Scope mocks base method.
+func (m *MockStats) Scope(arg0 string, arg1 stats.Tags) stats.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scope", arg0, arg1)
+	ret0, _ := ret[0].(stats.Stats)
+	return ret0
+}
+
+// Scope indicates an expected call of Scope.
+func (mr *MockStatsMockRecorder) Scope(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scope", reflect.TypeOf((*MockStats)(nil).Scope), arg0, arg1)
+}
+
 // Start mocks base method.
 func (m *MockStats) Start(arg0 context.Context, arg1 stats.GoRoutineFactory) error {
 	m.ctrl.T.Helper()
@@ -208,6 +222,18 @@ func (mr *MockMeasurementMockRecorder) Observe(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockMeasurement)(nil).Observe), arg0)
 }
 
+// ObserveCtx mocks base method.
+func (m *MockMeasurement) ObserveCtx(arg0 context.Context, arg1 float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveCtx", arg0, arg1)
+}
+
+// ObserveCtx indicates an expected call of ObserveCtx.
+func (mr *MockMeasurementMockRecorder) ObserveCtx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveCtx", reflect.TypeOf((*MockMeasurement)(nil).ObserveCtx), arg0, arg1)
+}
+
 // RecordDuration mocks base method.
 func (m *MockMeasurement) RecordDuration() func() {
 	m.ctrl.T.Helper()
@@ -234,6 +260,18 @@ func (mr *MockMeasurementMockRecorder) SendTiming(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTiming", reflect.TypeOf((*MockMeasurement)(nil).SendTiming), arg0)
 }
 
+// SendTimingCtx mocks base method.
+func (m *MockMeasurement) SendTimingCtx(arg0 context.Context, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SendTimingCtx", arg0, arg1)
+}
+
+// SendTimingCtx indicates an expected call of SendTimingCtx.
+func (mr *MockMeasurementMockRecorder) SendTimingCtx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTimingCtx", reflect.TypeOf((*MockMeasurement)(nil).SendTimingCtx), arg0, arg1)
+}
+
 // Since mocks base method.
 func (m *MockMeasurement) Since(arg0 time.Time) {
 	m.ctrl.T.Helper()
@@ -245,3 +283,15 @@ func (mr *MockMeasurementMockRecorder) Since(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Since", reflect.TypeOf((*MockMeasurement)(nil).Since), arg0)
 }
+
+// SinceCtx mocks base method.
+func (m *MockMeasurement) SinceCtx(arg0 context.Context, arg1 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SinceCtx", arg0, arg1)
+}
+
+// SinceCtx indicates an expected call of SinceCtx.
+func (mr *MockMeasurementMockRecorder) SinceCtx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SinceCtx", reflect.TypeOf((*MockMeasurement)(nil).SinceCtx), arg0, arg1)
+}
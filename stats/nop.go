@@ -13,13 +13,16 @@ type nop struct{}
 
 type nopMeasurement struct{}
 
-func (nopMeasurement) Count(_ int)                {}
-func (nopMeasurement) Increment()                 {}
-func (nopMeasurement) Gauge(_ any)                {}
-func (nopMeasurement) Observe(_ float64)          {}
-func (nopMeasurement) SendTiming(_ time.Duration) {}
-func (nopMeasurement) Since(_ time.Time)          {}
-func (nopMeasurement) RecordDuration() func()     { return func() {} }
+func (nopMeasurement) Count(_ int)                                      {}
+func (nopMeasurement) Increment()                                       {}
+func (nopMeasurement) Gauge(_ any)                                      {}
+func (nopMeasurement) Observe(_ float64)                                {}
+func (nopMeasurement) ObserveCtx(_ context.Context, _ float64)          {}
+func (nopMeasurement) SendTiming(_ time.Duration)                       {}
+func (nopMeasurement) SendTimingCtx(_ context.Context, _ time.Duration) {}
+func (nopMeasurement) Since(_ time.Time)                                {}
+func (nopMeasurement) SinceCtx(_ context.Context, _ time.Time)          {}
+func (nopMeasurement) RecordDuration() func()                           { return func() {} }
 
 func (*nop) NewStat(_, _ string) Measurement {
 	return &nopMeasurement{}
@@ -41,3 +44,5 @@ func (*nop) Start(_ context.Context, _ GoRoutineFactory) error { return nil }
 func (*nop) Stop()                                             {}
 
 func (*nop) RegisterCollector(c Collector) error { return nil }
+
+func (n *nop) Scope(_ string, _ Tags) Stats { return n }
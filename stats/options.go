@@ -1,24 +1,38 @@
 package stats
 
 import (
-	"sync/atomic"
-
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/khulnasoft/go-kit/config"
 )
 
 type statsConfig struct {
-	enabled             *atomic.Bool
+	// enabled can be changed at runtime to turn stats off and on for all measurements, including already created ones.
+	// If stats are disabled when the service is started though, re-enabling them has no effect.
+	enabled             config.ValueLoader[bool]
 	serviceName         string
 	serviceVersion      string
 	instanceName        string
 	namespaceIdentifier string
-	excludedTags        map[string]struct{}
+	// excludedTags can be changed at runtime but changes only apply to measurements created afterwards.
+	excludedTags config.ValueLoader[[]string]
 
 	periodicStatsConfig     periodicStatsConfig
 	defaultHistogramBuckets []float64
 	histogramBuckets        map[string][]float64
 	prometheusRegisterer    prometheus.Registerer
 	prometheusGatherer      prometheus.Gatherer
+	// disableBuildInfo disables the build_info, service_start_time_seconds and service_uptime_seconds gauges
+	disableBuildInfo bool
+}
+
+// loadExcludedTags returns the keys of the tags which should not be sent. It is meant to be called once per
+// measurement, the loader taking a lock.
+func (c *statsConfig) loadExcludedTags() []string {
+	if c.excludedTags == nil {
+		return nil
+	}
+	return c.excludedTags.Load()
 }
 
 // Option is a function used to configure the stats service.
@@ -63,3 +77,11 @@ func WithPrometheusRegistry(registerer prometheus.Registerer, gatherer prometheu
 		c.prometheusGatherer = gatherer
 	}
 }
+
+// WithoutBuildInfo disables the build_info, service_start_time_seconds and service_uptime_seconds gauges which are
+// otherwise reported by default.
+func WithoutBuildInfo() Option {
+	return func(c *statsConfig) {
+		c.disableBuildInfo = true
+	}
+}
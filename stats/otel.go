@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,9 +24,13 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/natefinch/lumberjack.v2"
 
+	"github.com/khulnasoft/go-kit/config"
 	"github.com/khulnasoft/go-kit/logger"
 	"github.com/khulnasoft/go-kit/stats/internal/otel"
+	"github.com/khulnasoft/go-kit/stats/internal/otel/otlpjson"
+	svcMetric "github.com/khulnasoft/go-kit/stats/metric"
 )
 
 const (
@@ -51,13 +59,19 @@ type otelStats struct {
 	histograms   map[string]metric.Float64Histogram
 	histogramsMu sync.Mutex
 
+	// started is true if the service was started with stats enabled
+	started                  atomic.Bool
 	otelManager              otel.Manager
 	collectorAggregator      *aggregatedCollector
 	runtimeStatsCollector    runtimeStatsCollector
 	metricsStatsCollector    metricStatsCollector
+	metricRegistration       metric.Registration
 	stopBackgroundCollection func()
 	logger                   logger.Logger
 
+	jsonLinesWriters map[string]*otlpjson.LineWriter
+	jsonLinesFiles   []io.Closer
+
 	httpServer                 *http.Server
 	httpServerShutdownComplete chan struct{}
 	prometheusRegisterer       prometheus.Registerer
@@ -92,23 +106,39 @@ func (s *otelStats) Start(ctx context.Context, goFactory GoRoutineFactory) error
 	}
 
 	options := []otel.Option{otel.WithInsecure(), otel.WithLogger(s.logger)}
-	if s.otelConfig.tracesEndpoint != "" {
+	tracesToJSONLines := isJSONLinesExporter(s.otelConfig.tracesExporter)
+	if s.otelConfig.tracesEndpoint != "" || tracesToJSONLines {
 		s.traceBaseAttributes = attrs
 		tpOpts := []otel.TracerProviderOption{
-			otel.WithTracingSamplingRate(s.otelConfig.tracingSamplingRate),
+			otel.WithTracingSampler(newTraceSampler(
+				s.otelConfig.tracingSamplingRate,
+				s.otelConfig.tracingSamplingRules,
+				s.otelConfig.tracingMaxTracesPerSecond,
+				s.logger,
+			)),
 		}
 		if s.otelConfig.withTracingSyncer {
 			tpOpts = append(tpOpts, otel.WithTracingSyncer())
 		}
-		if s.otelConfig.withZipkin {
-			tpOpts = append(tpOpts, otel.WithZipkin())
+		if tracesToJSONLines {
+			lw, err := s.jsonLinesWriter(s.otelConfig.tracesExporter, s.otelConfig.tracesFilePath)
+			if err != nil {
+				return err
+			}
+			spanExporter, err := otlpjson.NewSpanExporter(ctx, lw)
+			if err != nil {
+				return fmt.Errorf("failed to create json lines span exporter: %w", err)
+			}
+			options = append(options, otel.WithCustomTracerProvider(spanExporter, tpOpts...))
+		} else {
+			if s.otelConfig.withZipkin {
+				tpOpts = append(tpOpts, otel.WithZipkin())
+			}
+			options = append(options, otel.WithTracerProvider(s.otelConfig.tracesEndpoint, tpOpts...))
 		}
-		options = append(options,
-			otel.WithTracerProvider(s.otelConfig.tracesEndpoint, tpOpts...),
-			otel.WithTextMapPropagator(
-				propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
-			),
-		)
+		options = append(options, otel.WithTextMapPropagator(
+			propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		))
 	}
 
 	meterProviderOptions := []otel.MeterProviderOption{
@@ -126,7 +156,15 @@ func (s *otelStats) Start(ctx context.Context, goFactory GoRoutineFactory) error
 			)
 		}
 	}
-	if s.otelConfig.metricsEndpoint != "" {
+	if isJSONLinesExporter(s.otelConfig.metricsExporter) {
+		lw, err := s.jsonLinesWriter(s.otelConfig.metricsExporter, s.otelConfig.metricsFilePath)
+		if err != nil {
+			return err
+		}
+		options = append(options, otel.WithMeterProvider(append(meterProviderOptions,
+			otel.WithJSONLinesExporter(lw),
+		)...))
+	} else if s.otelConfig.metricsEndpoint != "" {
 		options = append(options, otel.WithMeterProvider(append(meterProviderOptions,
 			otel.WithGRPCMeterProvider(s.otelConfig.metricsEndpoint),
 		)...))
@@ -159,6 +197,9 @@ func (s *otelStats) Start(ctx context.Context, goFactory GoRoutineFactory) error
 			Handler: promhttp.InstrumentMetricHandler(
 				s.prometheusRegisterer, promhttp.HandlerFor(s.prometheusGatherer, promhttp.HandlerOpts{
 					ErrorLog: &prometheusLogger{l: s.logger},
+					// exemplars are only exposed in the OpenMetrics format, but beware that OpenMetrics adds
+					// the _total suffix to counters
+					EnableOpenMetrics: s.otelConfig.enablePrometheusOpenMetrics,
 				}),
 			),
 		}
@@ -177,20 +218,40 @@ func (s *otelStats) Start(ctx context.Context, goFactory GoRoutineFactory) error
 	gaugeFunc := func(key string, val uint64) {
 		s.getMeasurement("runtime_"+key, GaugeType, nil).Gauge(val)
 	}
-	s.metricsStatsCollector = newMetricStatsCollector(s, s.config.periodicStatsConfig.metricManager)
-	goFactory.Go(func() {
-		s.metricsStatsCollector.run(backgroundCollectionCtx)
-	})
+	if metricManager := s.config.periodicStatsConfig.metricManager; s.otelConfig.observeMetricRegistry && metricManager != nil {
+		s.metricRegistration, err = svcMetric.RegisterOTelInstruments(
+			metricManager.GetRegistry(svcMetric.PublishedMetrics), s.meter,
+			svcMetric.WithStaleTTL(s.otelConfig.metricRegistryStaleTTL),
+		)
+		if err != nil {
+			s.logger.Errorf("failed to observe metric registry, falling back to polling: %v", err)
+		}
+	}
+	if s.metricRegistration == nil {
+		s.metricsStatsCollector = newMetricStatsCollector(s, s.config.periodicStatsConfig.metricManager)
+		goFactory.Go(func() {
+			s.metricsStatsCollector.run(backgroundCollectionCtx)
+		})
+	}
 
 	gaugeTagsFunc := func(key string, tags Tags, val uint64) {
 		s.getMeasurement(key, GaugeType, tags).Gauge(val)
 	}
 	s.collectorAggregator.gaugeFunc = gaugeTagsFunc
+	if !s.config.disableBuildInfo {
+		if err := s.collectorAggregator.Add(newBuildInfoCollector(s.config.serviceName, s.config.serviceVersion)); err != nil {
+			s.logger.Errorf("failed to register build info collector: %v", err)
+		}
+	}
 	goFactory.Go(func() {
 		s.collectorAggregator.Run(backgroundCollectionCtx)
 	})
 
-	if s.config.periodicStatsConfig.enabled {
+	if s.config.periodicStatsConfig.enabled && s.config.periodicStatsConfig.useRuntimeMetrics {
+		if err := s.collectorAggregator.Add(s.config.periodicStatsConfig.newRuntimeMetricsCollector()); err != nil {
+			s.logger.Errorf("failed to register runtime metrics collector: %v", err)
+		}
+	} else if s.config.periodicStatsConfig.enabled {
 		s.runtimeStatsCollector = newRuntimeStatsCollector(gaugeFunc)
 		s.runtimeStatsCollector.PauseDur = time.Duration(s.config.periodicStatsConfig.statsCollectionInterval) * time.Second
 		s.runtimeStatsCollector.EnableCPU = s.config.periodicStatsConfig.enableCPUStats
@@ -201,6 +262,8 @@ func (s *otelStats) Start(ctx context.Context, goFactory GoRoutineFactory) error
 		})
 	}
 
+	s.started.Store(true)
+
 	if s.otelConfig.enablePrometheusExporter {
 		s.logger.Infof("Stats started in Prometheus mode on :%d", s.otelConfig.prometheusMetricsPort)
 	} else {
@@ -217,7 +280,7 @@ func (s *otelStats) RegisterCollector(c Collector) error {
 }
 
 func (s *otelStats) Stop() {
-	if !s.config.enabled.Load() {
+	if !s.started.Load() {
 		return
 	}
 
@@ -227,8 +290,18 @@ func (s *otelStats) Stop() {
 	if err := s.otelManager.Shutdown(ctx); err != nil {
 		s.logger.Errorf("failed to shutdown open telemetry: %v", err)
 	}
+	for _, c := range s.jsonLinesFiles {
+		if err := c.Close(); err != nil {
+			s.logger.Errorf("failed to close open telemetry json lines file: %v", err)
+		}
+	}
 
 	s.stopBackgroundCollection()
+	if s.metricRegistration != nil {
+		if err := s.metricRegistration.Unregister(); err != nil {
+			s.logger.Errorf("failed to stop observing metric registry: %v", err)
+		}
+	}
 	if s.metricsStatsCollector.done != nil {
 		<-s.metricsStatsCollector.done
 	}
@@ -292,10 +365,15 @@ func (s *otelStats) NewSampledTaggedStat(name, statType string, tags Tags) (m Me
 	return s.NewTaggedStat(name, statType, tags)
 }
 
+// Scope returns a Stats which prefixes the names of the measurements it creates and adds the provided tags to them
+func (s *otelStats) Scope(prefix string, tags Tags) Stats {
+	return Scope(s, prefix, tags)
+}
+
 func (*otelStats) getNoOpMeasurement(statType string) Measurement {
 	om := &otelMeasurement{
 		genericMeasurement: genericMeasurement{statType: statType},
-		disabled:           true,
+		enabled:            config.SingleValueLoader(false),
 	}
 	switch statType {
 	case CountType:
@@ -311,7 +389,9 @@ func (*otelStats) getNoOpMeasurement(statType string) Measurement {
 }
 
 func (s *otelStats) getMeasurement(name, statType string, tags Tags) Measurement {
-	if !s.config.enabled.Load() {
+	// measurements created while stats are disabled are still fully functional if the service was started,
+	// so that they start emitting again if stats are re-enabled at runtime
+	if !s.config.enabled.Load() && !s.started.Load() {
 		return s.getNoOpMeasurement(statType)
 	}
 
@@ -325,16 +405,17 @@ func (s *otelStats) getMeasurement(name, statType string, tags Tags) Measurement
 
 	// Clean up tags based on deployment type. No need to send workspace id tag for free tier customers.
 	newTags := make(Tags)
+	excludedTags := s.config.loadExcludedTags()
 	for k, v := range tags {
 		if strings.Trim(k, " ") == "" {
 			s.logger.Warnf("removing empty tag key with value %q for measurement %q", v, name)
 			continue
 		}
-		if _, ok := s.config.excludedTags[k]; ok {
+		if slices.Contains(excludedTags, k) {
 			continue
 		}
 		sanitizedKey := sanitizeTagKey(k)
-		if _, ok := s.config.excludedTags[sanitizedKey]; ok {
+		if slices.Contains(excludedTags, sanitizedKey) {
 			continue
 		}
 		if _, ok := s.resourceAttrs[sanitizedKey]; ok {
@@ -346,6 +427,7 @@ func (s *otelStats) getMeasurement(name, statType string, tags Tags) Measurement
 
 	om := &otelMeasurement{
 		genericMeasurement: genericMeasurement{statType: statType},
+		enabled:            s.config.enabled,
 		attributes:         newTags.otelAttributes(),
 	}
 
@@ -387,6 +469,7 @@ func (s *otelStats) getGauge(
 	if !ok {
 		og = &otelGauge{otelMeasurement: &otelMeasurement{
 			genericMeasurement: genericMeasurement{statType: GaugeType},
+			enabled:            s.config.enabled,
 			attributes:         attributes,
 		}}
 
@@ -456,14 +539,72 @@ func buildOTelInstrument[T any](
 }
 
 type otelStatsConfig struct {
-	tracesEndpoint           string
-	tracingSamplingRate      float64
-	withTracingSyncer        bool
-	withZipkin               bool
-	metricsEndpoint          string
-	metricsExportInterval    time.Duration
-	enablePrometheusExporter bool
-	prometheusMetricsPort    int
+	tracesEndpoint              string
+	tracingSamplingRate         config.ValueLoader[float64]
+	tracingSamplingRules        config.ValueLoader[string]
+	tracingMaxTracesPerSecond   config.ValueLoader[int]
+	withTracingSyncer           bool
+	withZipkin                  bool
+	metricsEndpoint             string
+	metricsExportInterval       time.Duration
+	enablePrometheusExporter    bool
+	enablePrometheusOpenMetrics bool
+	prometheusMetricsPort       int
+	// tracesExporter and metricsExporter can be either "file" or "stdout" to write OTLP-JSON lines instead of
+	// exporting to the configured endpoints, see jsonLinesWriter
+	tracesExporter  string
+	tracesFilePath  string
+	metricsExporter string
+	metricsFilePath string
+	fileMaxSizeMB   int
+	fileMaxBackups  int
+	// observeMetricRegistry makes the metric registry read at collection time via observable instruments,
+	// instead of being polled by the metricStatsCollector
+	observeMetricRegistry  bool
+	metricRegistryStaleTTL time.Duration
+}
+
+const (
+	jsonLinesExporterFile   = "file"
+	jsonLinesExporterStdout = "stdout"
+)
+
+func isJSONLinesExporter(exporter string) bool {
+	return exporter == jsonLinesExporterFile || exporter == jsonLinesExporterStdout
+}
+
+// jsonLinesWriter returns the writer for OTLP-JSON lines, i.e. either stdout or a rotating file at path.
+// Writers are shared by traces and metrics if they are written to the same destination.
+func (s *otelStats) jsonLinesWriter(exporter, path string) (*otlpjson.LineWriter, error) {
+	if s.jsonLinesWriters == nil {
+		s.jsonLinesWriters = make(map[string]*otlpjson.LineWriter)
+	}
+	key := jsonLinesExporterStdout
+	if exporter == jsonLinesExporterFile {
+		if path == "" {
+			return nil, fmt.Errorf("missing file path for the open telemetry %q exporter", exporter)
+		}
+		key = path
+	}
+	if lw, ok := s.jsonLinesWriters[key]; ok {
+		return lw, nil
+	}
+
+	var lw *otlpjson.LineWriter
+	if exporter == jsonLinesExporterStdout {
+		lw = otlpjson.NewLineWriter(os.Stdout)
+	} else {
+		file := &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    s.otelConfig.fileMaxSizeMB,
+			MaxBackups: s.otelConfig.fileMaxBackups,
+			LocalTime:  true,
+		}
+		s.jsonLinesFiles = append(s.jsonLinesFiles, file)
+		lw = otlpjson.NewLineWriter(file)
+	}
+	s.jsonLinesWriters[key] = lw
+	return lw, nil
 }
 
 type prometheusLogger struct{ l logger.Logger }
@@ -7,15 +7,23 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+
+	"github.com/khulnasoft/go-kit/config"
 )
 
 // otelMeasurement is the statsd-specific implementation of Measurement
 type otelMeasurement struct {
 	genericMeasurement
-	disabled   bool
+	enabled    config.ValueLoader[bool]
 	attributes []attribute.KeyValue
 }
 
+// disabled returns true if stats are disabled, it is checked on every operation so that stats can be
+// turned off and on at runtime
+func (m *otelMeasurement) disabled() bool {
+	return m.enabled == nil || !m.enabled.Load()
+}
+
 // otelCounter represents a counter stat
 type otelCounter struct {
 	*otelMeasurement
@@ -23,14 +31,14 @@ type otelCounter struct {
 }
 
 func (c *otelCounter) Count(n int) {
-	if !c.disabled {
+	if !c.disabled() {
 		c.counter.Add(context.TODO(), int64(n), metric.WithAttributes(c.attributes...))
 	}
 }
 
 // Increment increases the stat by 1. Is the Equivalent of Count(1). Only applies to CountType stats
 func (c *otelCounter) Increment() {
-	if !c.disabled {
+	if !c.disabled() {
 		c.counter.Add(context.TODO(), 1, metric.WithAttributes(c.attributes...))
 	}
 }
@@ -43,14 +51,14 @@ type otelGauge struct {
 
 // Gauge records an absolute value for this stat. Only applies to GaugeType stats
 func (g *otelGauge) Gauge(value interface{}) {
-	if g.disabled {
+	if g.disabled() {
 		return
 	}
 	g.value.Store(value)
 }
 
 func (g *otelGauge) getValue() interface{} {
-	if g.disabled {
+	if g.disabled() {
 		return nil
 	}
 	return g.value.Load()
@@ -65,15 +73,25 @@ type otelTimer struct {
 
 // Since sends the time elapsed since duration start. Only applies to TimerType stats
 func (t *otelTimer) Since(start time.Time) {
-	if !t.disabled {
-		t.SendTiming(time.Since(start))
+	t.SinceCtx(context.TODO(), start)
+}
+
+// SinceCtx sends the time elapsed since duration start, using ctx for exemplars. Only applies to TimerType stats
+func (t *otelTimer) SinceCtx(ctx context.Context, start time.Time) {
+	if !t.disabled() {
+		t.SendTimingCtx(ctx, time.Since(start))
 	}
 }
 
 // SendTiming sends a timing for this stat. Only applies to TimerType stats
 func (t *otelTimer) SendTiming(duration time.Duration) {
-	if !t.disabled {
-		t.timer.Record(context.TODO(), duration.Seconds(), metric.WithAttributes(t.attributes...))
+	t.SendTimingCtx(context.TODO(), duration)
+}
+
+// SendTimingCtx sends a timing for this stat, using ctx for exemplars. Only applies to TimerType stats
+func (t *otelTimer) SendTimingCtx(ctx context.Context, duration time.Duration) {
+	if !t.disabled() {
+		t.timer.Record(ctx, duration.Seconds(), metric.WithAttributes(t.attributes...))
 	}
 }
 
@@ -81,7 +99,7 @@ func (t *otelTimer) SendTiming(duration time.Duration) {
 // the call to this function and the execution of the function it returns.
 // Only applies to TimerType stats
 func (t *otelTimer) RecordDuration() func() {
-	if t.disabled {
+	if t.disabled() {
 		return func() {}
 	}
 	var start time.Time
@@ -103,7 +121,12 @@ type otelHistogram struct {
 
 // Observe sends an observation
 func (h *otelHistogram) Observe(value float64) {
-	if !h.disabled {
-		h.histogram.Record(context.TODO(), value, metric.WithAttributes(h.attributes...))
+	h.ObserveCtx(context.TODO(), value)
+}
+
+// ObserveCtx sends an observation, using ctx for exemplars
+func (h *otelHistogram) ObserveCtx(ctx context.Context, value float64) {
+	if !h.disabled() {
+		h.histogram.Record(ctx, value, metric.WithAttributes(h.attributes...))
 	}
 }
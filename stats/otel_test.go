@@ -24,6 +24,7 @@ import (
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.uber.org/mock/gomock"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/khulnasoft/go-kit/httputil"
 	"github.com/khulnasoft/go-kit/logger"
 	"github.com/khulnasoft/go-kit/logger/mock_logger"
+	"github.com/khulnasoft/go-kit/stats/internal/otel/otlpjson"
 	"github.com/khulnasoft/go-kit/stats/metric"
 	statsTest "github.com/khulnasoft/go-kit/stats/testhelper"
 	"github.com/khulnasoft/go-kit/testhelper"
@@ -112,6 +114,12 @@ func TestOTelMeasurementInvalidOperations(t *testing.T) {
 		require.Panics(t, func() {
 			s.NewStat("test", HistogramType).Since(time.Now())
 		})
+		require.Panics(t, func() {
+			s.NewStat("test", HistogramType).SinceCtx(context.Background(), time.Now())
+		})
+		require.Panics(t, func() {
+			s.NewStat("test", HistogramType).SendTimingCtx(context.Background(), 1)
+		})
 	})
 
 	t.Run("timer invalid operations", func(t *testing.T) {
@@ -127,6 +135,9 @@ func TestOTelMeasurementInvalidOperations(t *testing.T) {
 		require.Panics(t, func() {
 			s.NewStat("test", TimerType).Observe(1.2)
 		})
+		require.Panics(t, func() {
+			s.NewStat("test", TimerType).ObserveCtx(context.Background(), 1.2)
+		})
 	})
 }
 
@@ -853,6 +864,193 @@ func TestPrometheusDuplicatedAttributes(t *testing.T) {
 	), metrics[metricName].Metric[0].Label, "Got %+v", metrics[metricName].Metric[0].Label)
 }
 
+func TestPrometheusExemplars(t *testing.T) {
+	c := config.New()
+	c.Set("OpenTelemetry.enabled", true)
+	c.Set("OpenTelemetry.metrics.prometheus.enabled", true)
+	c.Set("OpenTelemetry.metrics.exportInterval", time.Millisecond)
+	c.Set("RuntimeStats.enabled", false)
+	r := prometheus.NewRegistry()
+	s := NewStats(c, logger.NewFactory(c), metric.NewManager(),
+		WithServiceName(t.Name()),
+		WithPrometheusRegistry(r, r),
+		WithDefaultHistogramBuckets([]float64{1, 10}),
+	)
+	require.NoError(t, s.Start(context.Background(), DefaultGoRoutineFactory))
+	t.Cleanup(s.Stop)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer(t.Name()).Start(context.Background(), "my-span")
+	defer span.End()
+
+	s.NewStat("my_histogram", HistogramType).ObserveCtx(ctx, 5)
+	s.NewStat("my_histogram", HistogramType).Observe(5) // no span, no exemplar
+	s.NewStat("my_timer", TimerType).SendTimingCtx(ctx, 2*time.Second)
+	s.NewStat("my_timer_since", TimerType).SinceCtx(context.Background(), time.Now())
+
+	metrics, err := r.Gather()
+	require.NoError(t, err)
+
+	exemplars := make(map[string][]*promClient.Exemplar)
+	for _, mf := range metrics {
+		for _, m := range mf.GetMetric() {
+			for _, b := range m.GetHistogram().GetBucket() {
+				if b.GetExemplar() != nil {
+					exemplars[mf.GetName()] = append(exemplars[mf.GetName()], b.GetExemplar())
+				}
+			}
+		}
+	}
+	require.Len(t, exemplars, 2, "Got %+v", exemplars)
+	for _, name := range []string{"my_histogram", "my_timer"} {
+		require.Lenf(t, exemplars[name], 1, "Got %+v", exemplars[name])
+		require.ElementsMatch(t, []*promClient.LabelPair{
+			{Name: ptr("span_id"), Value: ptr(span.SpanContext().SpanID().String())},
+			{Name: ptr("trace_id"), Value: ptr(span.SpanContext().TraceID().String())},
+		}, exemplars[name][0].GetLabel())
+	}
+	require.EqualValues(t, 5, exemplars["my_histogram"][0].GetValue())
+	require.EqualValues(t, 2, exemplars["my_timer"][0].GetValue())
+}
+
+func TestOTelHotReload(t *testing.T) {
+	c := config.New()
+	c.Set("OpenTelemetry.enabled", true)
+	c.Set("OpenTelemetry.metrics.prometheus.enabled", true)
+	c.Set("OpenTelemetry.metrics.exportInterval", time.Millisecond)
+	c.Set("RuntimeStats.enabled", false)
+	r := prometheus.NewRegistry()
+	s := NewStats(c, logger.NewFactory(c), metric.NewManager(),
+		WithServiceName(t.Name()),
+		WithPrometheusRegistry(r, r),
+	)
+	require.NoError(t, s.Start(context.Background(), DefaultGoRoutineFactory))
+	t.Cleanup(s.Stop)
+
+	getMetrics := func(t *testing.T) map[string]*promClient.MetricFamily {
+		t.Helper()
+		metrics, err := r.Gather()
+		require.NoError(t, err)
+		mfs := make(map[string]*promClient.MetricFamily)
+		for _, mf := range metrics {
+			mfs[mf.GetName()] = mf
+		}
+		return mfs
+	}
+
+	counter := s.NewStat("reload_counter", CountType)
+	counter.Count(1)
+	s.NewStat("reload_gauge", GaugeType).Gauge(1)
+	metrics := getMetrics(t)
+	require.EqualValues(t, 1, metrics["reload_counter"].GetMetric()[0].GetCounter().GetValue())
+	require.EqualValues(t, 1, metrics["reload_gauge"].GetMetric()[0].GetGauge().GetValue())
+
+	c.Set("enableStats", false)
+	counter.Count(2)
+	newCounter := s.NewStat("reload_new_counter", CountType)
+	newCounter.Count(3)
+	metrics = getMetrics(t)
+	require.EqualValues(t, 1, metrics["reload_counter"].GetMetric()[0].GetCounter().GetValue())
+	require.NotContains(t, metrics, "reload_new_counter")
+	require.NotContains(t, metrics, "reload_gauge", "gauges should not be reported while stats are disabled")
+
+	c.Set("enableStats", true)
+	counter.Count(4)
+	newCounter.Count(5)
+	metrics = getMetrics(t)
+	require.EqualValues(t, 5, metrics["reload_counter"].GetMetric()[0].GetCounter().GetValue())
+	require.EqualValues(t, 5, metrics["reload_new_counter"].GetMetric()[0].GetCounter().GetValue())
+	require.EqualValues(t, 1, metrics["reload_gauge"].GetMetric()[0].GetGauge().GetValue())
+
+	c.Set("statsExcludedTags", []string{"workspaceId"})
+	s.NewTaggedStat("reload_excluded", CountType, Tags{"workspaceId": "value", "a": "b"}).Increment()
+	metrics = getMetrics(t)
+	require.Len(t, metrics["reload_excluded"].GetMetric(), 1)
+	for _, l := range metrics["reload_excluded"].GetMetric()[0].GetLabel() {
+		require.NotEqual(t, "workspaceId", l.GetName())
+	}
+}
+
+func TestPrometheusRuntimeMetrics(t *testing.T) {
+	c := config.New()
+	c.Set("OpenTelemetry.enabled", true)
+	c.Set("OpenTelemetry.metrics.prometheus.enabled", true)
+	c.Set("OpenTelemetry.metrics.exportInterval", time.Millisecond)
+	c.Set("RuntimeStats.useRuntimeMetrics", true)
+	c.Set("RuntimeStats.enableGCStats", false)
+	r := prometheus.NewRegistry()
+	s := NewStats(c, logger.NewFactory(c), metric.NewManager(),
+		WithServiceName(t.Name()),
+		WithPrometheusRegistry(r, r),
+	)
+	require.NoError(t, s.Start(context.Background(), DefaultGoRoutineFactory))
+	t.Cleanup(s.Stop)
+
+	var metrics map[string]*promClient.MetricFamily
+	require.Eventually(t, func() bool {
+		mfs, err := r.Gather()
+		if err != nil {
+			return false
+		}
+		metrics = make(map[string]*promClient.MetricFamily)
+		for _, mf := range mfs {
+			metrics[mf.GetName()] = mf
+		}
+		_, ok := metrics["runtime_sched_latencies_nanoseconds"]
+		return ok
+	}, 10*time.Second, 10*time.Millisecond)
+
+	require.Greater(t, metrics["runtime_sched_goroutines_goroutines"].GetMetric()[0].GetGauge().GetValue(), 0.0)
+	require.Len(t, metrics["runtime_sched_latencies_nanoseconds"].GetMetric(), 4, "one gauge per quantile")
+	require.Contains(t, metrics, "runtime_memory_classes_heap_objects_bytes")
+	require.NotContains(t, metrics, "runtime_gc_cycles_total_gc_cycles", "GC stats are disabled")
+	require.NotContains(t, metrics, "runtime_cpu_goroutines", "runtime.MemStats based collector should not run")
+}
+
+func TestPrometheusObservableMetricRegistry(t *testing.T) {
+	c := config.New()
+	c.Set("OpenTelemetry.enabled", true)
+	c.Set("OpenTelemetry.metrics.prometheus.enabled", true)
+	c.Set("OpenTelemetry.metrics.registry.observable", true)
+	c.Set("RuntimeStats.enabled", false)
+	r := prometheus.NewRegistry()
+	m := metric.NewManager()
+	s := NewStats(c, logger.NewFactory(c), m, WithServiceName(t.Name()), WithPrometheusRegistry(r, r))
+	require.NoError(t, s.Start(context.Background(), DefaultGoRoutineFactory))
+	t.Cleanup(s.Stop)
+
+	gather := func() map[string]*promClient.MetricFamily {
+		mfs, err := r.Gather()
+		require.NoError(t, err)
+		metrics := make(map[string]*promClient.MetricFamily)
+		for _, mf := range mfs {
+			metrics[mf.GetName()] = mf
+		}
+		return metrics
+	}
+
+	registry := m.GetRegistry(metric.PublishedMetrics)
+	gauge := registry.MustGetGauge(TestMeasurement{tablePrefix: "gauge", workspace: "workspace", destType: "destType"})
+	gauge.Set(1)
+	registry.MustGetCounter(TestMeasurement{tablePrefix: "counter", workspace: "workspace", destType: "destType"}).Add(2)
+
+	// values are read at scrape time, without waiting for any polling interval
+	metrics := gather()
+	require.Equal(t, 1.0, metrics["test_measurement_gauge"].GetMetric()[0].GetGauge().GetValue())
+	require.Equal(t, 2.0, metrics["test_measurement_counter"].GetMetric()[0].GetCounter().GetValue())
+	require.Subset(t, metrics["test_measurement_gauge"].GetMetric()[0].GetLabel(), []*promClient.LabelPair{
+		{Name: ptr("destType"), Value: ptr("destType")},
+		{Name: ptr("workspaceId"), Value: ptr("workspace")},
+	})
+
+	gauge.Set(3)
+	registry.Delete(TestMeasurement{tablePrefix: "counter", workspace: "workspace", destType: "destType"})
+	metrics = gather()
+	require.Equal(t, 3.0, metrics["test_measurement_gauge"].GetMetric()[0].GetGauge().GetValue())
+	require.NotContains(t, metrics, "test_measurement_counter")
+}
+
 func TestNoopTracingNoPanics(t *testing.T) {
 	freePort, err := testhelper.GetFreePort()
 	require.NoError(t, err)
@@ -1079,3 +1277,47 @@ func (m containsMatcher) String() string { return string(m) }
 func (m containsMatcher) Matches(arg any) bool {
 	return strings.Contains(arg.(string), string(m))
 }
+
+func TestOTelJSONLinesFileExporters(t *testing.T) {
+	dir := t.TempDir()
+	tracesPath := filepath.Join(dir, "traces.jsonl")
+	metricsPath := filepath.Join(dir, "metrics.jsonl")
+
+	c := config.New()
+	c.Set("OpenTelemetry.enabled", true)
+	c.Set("RuntimeStats.enabled", false)
+	c.Set("OpenTelemetry.traces.exporter", "file")
+	c.Set("OpenTelemetry.traces.file.path", tracesPath)
+	c.Set("OpenTelemetry.traces.samplingRate", 1.0)
+	c.Set("OpenTelemetry.traces.withSyncer", true)
+	c.Set("OpenTelemetry.metrics.exporter", "file")
+	c.Set("OpenTelemetry.metrics.file.path", metricsPath)
+	c.Set("OpenTelemetry.metrics.exportInterval", time.Millisecond)
+	s := NewStats(c, logger.NewFactory(c), metric.NewManager(), WithServiceName(t.Name()))
+	require.NoError(t, s.Start(context.Background(), DefaultGoRoutineFactory))
+
+	tracer := s.NewTracer("my-tracer")
+	ctx, root := tracer.Start(context.Background(), "root", SpanKindServer)
+	_, child := tracer.Start(ctx, "child", SpanKindInternal)
+	child.End()
+	root.End()
+	s.NewStat("my_counter", CountType).Count(3)
+
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(metricsPath)
+		return err == nil && strings.Contains(string(data), `"my_counter"`)
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Stop()
+
+	f, err := os.Open(tracesPath)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	spans, err := otlpjson.ReadSpans(f)
+	require.NoError(t, err)
+	roots := otlpjson.BuildTrees(spans)
+	require.Len(t, roots, 1)
+	require.Equal(t, "root", roots[0].Name)
+	require.Equal(t, t.Name(), roots[0].Service)
+	require.Len(t, roots[0].Children, 1)
+	require.Equal(t, "child", roots[0].Children[0].Name)
+}
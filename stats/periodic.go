@@ -5,6 +5,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/khulnasoft/go-kit/config"
 	"github.com/khulnasoft/go-kit/stats/metric"
 )
 
@@ -18,12 +19,19 @@ type gaugeFunc func(key string, val uint64)
 type periodicStatsConfig struct {
 	enabled                 bool
 	statsCollectionInterval int64
-	enableCPUStats          bool
-	enableMemStats          bool
-	enableGCStats           bool
+	enableCPUStats          config.ValueLoader[bool]
+	enableMemStats          config.ValueLoader[bool]
+	enableGCStats           config.ValueLoader[bool]
+	useRuntimeMetrics       bool
 	metricManager           metric.Manager
 }
 
+// newRuntimeMetricsCollector creates a runtime/metrics based collector honouring the configured toggles.
+// It is used in place of runtimeStatsCollector when useRuntimeMetrics is set.
+func (c periodicStatsConfig) newRuntimeMetricsCollector() *runtimeMetricsCollector {
+	return newRuntimeMetricsCollector(c.enableCPUStats, c.enableMemStats, c.enableGCStats)
+}
+
 // runtimeStatsCollector implements the periodic grabbing of informational data from the
 // runtime package and outputting the values to a GaugeFunc.
 type runtimeStatsCollector struct {
@@ -32,14 +40,17 @@ type runtimeStatsCollector struct {
 	PauseDur time.Duration
 
 	// EnableCPU determines whether CPU statistics will be output. Defaults to true.
-	EnableCPU bool
+	// It is loaded before each collection, so it can be changed at runtime.
+	EnableCPU config.ValueLoader[bool]
 
 	// EnableMem determines whether memory statistics will be output. Defaults to true.
-	EnableMem bool
+	// It is loaded before each collection, so it can be changed at runtime.
+	EnableMem config.ValueLoader[bool]
 
 	// EnableGC determines whether garbage collection statistics will be output. EnableMem
 	// must also be set to true for this to take affect. Defaults to true.
-	EnableGC bool
+	// It is loaded before each collection, so it can be changed at runtime.
+	EnableGC config.ValueLoader[bool]
 
 	// done, when closed, is used to signal runtimeStatsCollector that is should stop collecting
 	// statistics and the Run function should return. If done is set, upon shutdown
@@ -55,9 +66,9 @@ type runtimeStatsCollector struct {
 func newRuntimeStatsCollector(gaugeFunc gaugeFunc) runtimeStatsCollector {
 	return runtimeStatsCollector{
 		PauseDur:  10 * time.Second,
-		EnableCPU: true,
-		EnableMem: true,
-		EnableGC:  true,
+		EnableCPU: config.SingleValueLoader(true),
+		EnableMem: config.SingleValueLoader(true),
+		EnableGC:  config.SingleValueLoader(true),
 		gaugeFunc: gaugeFunc,
 		done:      make(chan struct{}),
 	}
@@ -93,32 +104,32 @@ type cpuStats struct {
 // zeroStats sets all the stat guages to zero. On shutdown we want to zero them out so they don't persist
 // at their last value until we start back up.
 func (c runtimeStatsCollector) zeroStats() {
-	if c.EnableCPU {
+	if c.EnableCPU.Load() {
 		cStats := cpuStats{}
 		c.outputCPUStats(&cStats)
 	}
-	if c.EnableMem {
+	if c.EnableMem.Load() {
 		mStats := runtime.MemStats{}
 		c.outputMemStats(&mStats)
-		if c.EnableGC {
+		if c.EnableGC.Load() {
 			c.outputGCStats(&mStats)
 		}
 	}
 }
 
 func (c runtimeStatsCollector) outputStats() {
-	if c.EnableCPU {
+	if c.EnableCPU.Load() {
 		cStats := cpuStats{
 			NumGoroutine: uint64(runtime.NumGoroutine()),
 			NumCgoCall:   uint64(runtime.NumCgoCall()),
 		}
 		c.outputCPUStats(&cStats)
 	}
-	if c.EnableMem {
+	if c.EnableMem.Load() {
 		m := &runtime.MemStats{}
 		runtime.ReadMemStats(m)
 		c.outputMemStats(m)
-		if c.EnableGC {
+		if c.EnableGC.Load() {
 			c.outputGCStats(m)
 		}
 	}
@@ -0,0 +1,233 @@
+package stats
+
+import (
+	"math"
+	"runtime/metrics"
+	"strings"
+	"sync"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+// runtimeMetricsQuantiles are the quantiles reported for runtime/metrics histograms
+var runtimeMetricsQuantiles = []struct {
+	tag   string
+	value float64
+}{
+	{"0.5", 0.5},
+	{"0.9", 0.9},
+	{"0.99", 0.99},
+	{"1", 1},
+}
+
+// runtimeMetricsGroups are the runtime/metrics collected by runtimeMetricsCollector, grouped by the RuntimeStats toggle
+// that enables them. Metrics not supported by the running Go version are ignored.
+var runtimeMetricsGroups = struct {
+	cpu, mem, gc []string
+}{
+	cpu: []string{
+		"/sched/goroutines:goroutines",
+		"/sched/gomaxprocs:threads",
+		"/sched/latencies:seconds",
+		"/sync/mutex/wait/total:seconds",
+		"/cgo/go-to-c-calls:calls",
+	},
+	mem: []string{
+		"/memory/classes/total:bytes",
+		"/memory/classes/heap/objects:bytes",
+		"/memory/classes/heap/free:bytes",
+		"/memory/classes/heap/released:bytes",
+		"/memory/classes/heap/stacks:bytes",
+		"/memory/classes/heap/unused:bytes",
+		"/memory/classes/os-stacks:bytes",
+		"/memory/classes/metadata/other:bytes",
+		"/memory/classes/other:bytes",
+		"/memory/classes/profiling/buckets:bytes",
+		"/gc/heap/allocs:bytes",
+		"/gc/heap/frees:bytes",
+		"/gc/heap/objects:objects",
+	},
+	gc: []string{
+		"/gc/cycles/total:gc-cycles",
+		"/gc/heap/goal:bytes",
+		"/gc/gogc:percent",
+		"/gc/gomemlimit:bytes",
+		"/sched/pauses/total/gc:seconds",
+		"/cpu/classes/gc/total:cpu-seconds",
+	},
+}
+
+// runtimeMetricsCollector is a Collector reading its values from the runtime/metrics package which, contrary to
+// runtime.ReadMemStats, doesn't stop the world.
+//
+// Metric names are derived from the runtime/metrics ones, e.g. "/memory/classes/heap/objects:bytes" becomes
+// "runtime_memory_classes_heap_objects_bytes". Since gauges can only hold integers, values in seconds are reported
+// in nanoseconds (e.g. "runtime_sched_latencies_nanoseconds").
+// Histograms (i.e. scheduler latencies and GC pauses) are reported as one gauge per quantile (tagged with quantile=0.5,
+// 0.9, 0.99 and 1) computed over the observations made since the previous collection, along with a "_count" gauge
+// holding the total number of observations.
+type runtimeMetricsCollector struct {
+	enableCPU config.ValueLoader[bool]
+	enableMem config.ValueLoader[bool]
+	enableGC  config.ValueLoader[bool]
+
+	mu             sync.Mutex // protects the following
+	samples        []metrics.Sample
+	previousCounts map[string][]uint64
+}
+
+func newRuntimeMetricsCollector(enableCPU, enableMem, enableGC config.ValueLoader[bool]) *runtimeMetricsCollector {
+	supported := make(map[string]struct{})
+	for _, d := range metrics.All() {
+		supported[d.Name] = struct{}{}
+	}
+	var samples []metrics.Sample
+	for _, group := range [][]string{runtimeMetricsGroups.cpu, runtimeMetricsGroups.mem, runtimeMetricsGroups.gc} {
+		for _, name := range group {
+			if _, ok := supported[name]; ok {
+				samples = append(samples, metrics.Sample{Name: name})
+			}
+		}
+	}
+	return &runtimeMetricsCollector{
+		enableCPU:      enableCPU,
+		enableMem:      enableMem,
+		enableGC:       enableGC,
+		samples:        samples,
+		previousCounts: make(map[string][]uint64),
+	}
+}
+
+func (c *runtimeMetricsCollector) Collect(gaugeFunc gaugeTagsFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metrics.Read(c.samples)
+	for _, sample := range c.samples {
+		if !c.isEnabled(sample.Name) {
+			continue
+		}
+		name, scale := runtimeMetricName(sample.Name)
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			gaugeFunc(name, nil, sample.Value.Uint64())
+		case metrics.KindFloat64:
+			gaugeFunc(name, nil, toUint64(sample.Value.Float64()*scale))
+		case metrics.KindFloat64Histogram:
+			c.outputHistogram(gaugeFunc, sample.Name, name, scale, sample.Value.Float64Histogram())
+		}
+	}
+}
+
+func (c *runtimeMetricsCollector) Zero(gaugeFunc gaugeTagsFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sample := range c.samples {
+		if !c.isEnabled(sample.Name) {
+			continue
+		}
+		name, _ := runtimeMetricName(sample.Name)
+		if sample.Value.Kind() != metrics.KindFloat64Histogram {
+			gaugeFunc(name, nil, 0)
+			continue
+		}
+		for _, q := range runtimeMetricsQuantiles {
+			gaugeFunc(name, Tags{"quantile": q.tag}, 0)
+		}
+		gaugeFunc(histogramCountName(name), nil, 0)
+	}
+	c.previousCounts = make(map[string][]uint64)
+}
+
+func (c *runtimeMetricsCollector) ID() string {
+	return "runtime_metrics"
+}
+
+func (c *runtimeMetricsCollector) isEnabled(name string) bool {
+	for _, group := range []struct {
+		names   []string
+		enabled config.ValueLoader[bool]
+	}{
+		{runtimeMetricsGroups.cpu, c.enableCPU},
+		{runtimeMetricsGroups.mem, c.enableMem},
+		{runtimeMetricsGroups.gc, c.enableGC},
+	} {
+		for _, n := range group.names {
+			if n == name {
+				return group.enabled.Load()
+			}
+		}
+	}
+	return false
+}
+
+// outputHistogram outputs the quantiles of the observations made since the previous collection, plus the total count
+func (c *runtimeMetricsCollector) outputHistogram(
+	gaugeFunc gaugeTagsFunc, key, name string, scale float64, h *metrics.Float64Histogram,
+) {
+	previous := c.previousCounts[key]
+	if len(previous) != len(h.Counts) {
+		previous = make([]uint64, len(h.Counts))
+	}
+	deltas := make([]uint64, len(h.Counts))
+	var total, deltaTotal uint64
+	for i, count := range h.Counts {
+		deltas[i] = count - previous[i]
+		deltaTotal += deltas[i]
+		total += count
+	}
+	c.previousCounts[key] = append(previous[:0], h.Counts...)
+
+	for _, q := range runtimeMetricsQuantiles {
+		gaugeFunc(name, Tags{"quantile": q.tag}, toUint64(histogramQuantile(h.Buckets, deltas, deltaTotal, q.value)*scale))
+	}
+	gaugeFunc(histogramCountName(name), nil, total)
+}
+
+// histogramQuantile returns the upper bound of the bucket containing the given quantile, or its lower bound if the
+// bucket is unbounded.
+func histogramQuantile(buckets []float64, counts []uint64, total uint64, quantile float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	threshold := uint64(math.Ceil(quantile * float64(total)))
+	var cumulative uint64
+	for i, count := range counts {
+		cumulative += count
+		if count == 0 || cumulative < threshold {
+			continue
+		}
+		if math.IsInf(buckets[i+1], 1) {
+			return buckets[i]
+		}
+		return buckets[i+1]
+	}
+	return 0
+}
+
+// runtimeMetricName converts a runtime/metrics name into a metric name, along with the scale to apply to its values
+func runtimeMetricName(name string) (string, float64) {
+	path, unit, _ := strings.Cut(name, ":")
+	scale := 1.0
+	if strings.HasSuffix(unit, "seconds") {
+		unit = strings.TrimSuffix(unit, "seconds") + "nanoseconds"
+		scale = 1e9
+	}
+	replacer := strings.NewReplacer("/", "_", "-", "_")
+	return "runtime" + replacer.Replace(path) + "_" + replacer.Replace(unit), scale
+}
+
+func histogramCountName(name string) string {
+	return name + "_count"
+}
+
+func toUint64(v float64) uint64 {
+	if v <= 0 || math.IsNaN(v) {
+		return 0
+	}
+	if v >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(v)
+}
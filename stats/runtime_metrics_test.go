@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/config"
+)
+
+func TestRuntimeMetricsCollector(t *testing.T) {
+	type gauge struct {
+		name     string
+		quantile string
+	}
+	collect := func(f func(gaugeTagsFunc)) map[gauge]uint64 {
+		values := make(map[gauge]uint64)
+		f(func(key string, tags Tags, val uint64) {
+			values[gauge{name: key, quantile: tags["quantile"]}] = val
+		})
+		return values
+	}
+
+	t.Run("all enabled", func(t *testing.T) {
+		c := newRuntimeMetricsCollector(
+			config.SingleValueLoader(true), config.SingleValueLoader(true), config.SingleValueLoader(true),
+		)
+		require.Equal(t, "runtime_metrics", c.ID())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				time.Sleep(time.Millisecond)
+			}()
+		}
+		runtime.GC()
+
+		values := collect(c.Collect)
+		require.Greater(t, values[gauge{name: "runtime_sched_goroutines_goroutines"}], uint64(0))
+		require.Greater(t, values[gauge{name: "runtime_memory_classes_heap_objects_bytes"}], uint64(0))
+		require.Greater(t, values[gauge{name: "runtime_gc_cycles_total_gc_cycles"}], uint64(0))
+		require.Contains(t, values, gauge{name: "runtime_sync_mutex_wait_total_nanoseconds"})
+		require.Contains(t, values, gauge{name: "runtime_cpu_classes_gc_total_cpu_nanoseconds"})
+		for _, histogram := range []string{"runtime_sched_latencies_nanoseconds", "runtime_sched_pauses_total_gc_nanoseconds"} {
+			for _, q := range []string{"0.5", "0.9", "0.99", "1"} {
+				require.Contains(t, values, gauge{name: histogram, quantile: q})
+			}
+			require.Greater(t, values[gauge{name: histogram + "_count"}], uint64(0))
+			require.LessOrEqual(t, values[gauge{name: histogram, quantile: "0.5"}], values[gauge{name: histogram, quantile: "1"}])
+		}
+		wg.Wait()
+
+		zeroed := collect(c.Zero)
+		require.Len(t, zeroed, len(values))
+		for g, v := range zeroed {
+			require.Zerof(t, v, "%+v should be zero", g)
+		}
+	})
+
+	t.Run("toggles", func(t *testing.T) {
+		enableCPU, enableMem := config.New(), config.New()
+		c := newRuntimeMetricsCollector(
+			enableCPU.GetReloadableBoolVar(true, "enabled"),
+			enableMem.GetReloadableBoolVar(false, "enabled"),
+			config.SingleValueLoader(false),
+		)
+
+		values := collect(c.Collect)
+		require.Contains(t, values, gauge{name: "runtime_sched_goroutines_goroutines"})
+		require.NotContains(t, values, gauge{name: "runtime_memory_classes_heap_objects_bytes"})
+		require.NotContains(t, values, gauge{name: "runtime_gc_cycles_total_gc_cycles"})
+
+		enableCPU.Set("enabled", false)
+		enableMem.Set("enabled", true)
+		values = collect(c.Collect)
+		require.NotContains(t, values, gauge{name: "runtime_sched_goroutines_goroutines"})
+		require.Contains(t, values, gauge{name: "runtime_memory_classes_heap_objects_bytes"})
+	})
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := []float64{math.Inf(-1), 1, 2, 3, math.Inf(1)}
+
+	require.EqualValues(t, 0, histogramQuantile(buckets, []uint64{0, 0, 0, 0}, 0, 0.5))
+	require.EqualValues(t, 2, histogramQuantile(buckets, []uint64{0, 5, 5, 0}, 10, 0.5))
+	require.EqualValues(t, 3, histogramQuantile(buckets, []uint64{0, 5, 5, 0}, 10, 0.9))
+	require.EqualValues(t, 3, histogramQuantile(buckets, []uint64{0, 5, 4, 1}, 10, 1), "unbounded buckets report their lower bound")
+	require.EqualValues(t, 1, histogramQuantile(buckets, []uint64{1, 0, 0, 0}, 1, 0.5))
+}
@@ -0,0 +1,186 @@
+package stats
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+// traceSamplingRule overrides the sampling rate of the spans matching both its span name and tags.
+type traceSamplingRule struct {
+	// SpanName is the name the span must have, an empty name matches all spans.
+	SpanName string `json:"spanName"`
+	// Tags are the tags (i.e. string attributes) the span must have when started.
+	Tags Tags `json:"tags"`
+	// SamplingRate is the ratio of matching traces to sample: >= 1 always samples, <= 0 never samples.
+	SamplingRate float64 `json:"samplingRate"`
+}
+
+// matches returns true if the span name and attributes satisfy the rule
+func (r *traceSamplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if r.SpanName != "" && r.SpanName != p.Name {
+		return false
+	}
+	for k, v := range r.Tags {
+		found := false
+		for _, attr := range p.Attributes {
+			if string(attr.Key) == k && attr.Value.Emit() == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// traceSampler is a parent based sampler whose configuration can be changed at runtime:
+//
+//   - spans whose parent is sampled are always sampled, so that traces are never broken;
+//   - root spans are sampled according to the first matching rule or the default sampling rate if none matches;
+//   - spans whose parent is not sampled are only sampled if a rule matches them, e.g. to trace a given workspace
+//     even if the upstream service decided not to sample it;
+//   - new sampling decisions (i.e. not inherited from a sampled parent) are limited to maxTracesPerSecond, if positive.
+type traceSampler struct {
+	samplingRate       config.ValueLoader[float64]
+	rules              config.ValueLoader[string]
+	maxTracesPerSecond config.ValueLoader[int]
+	logger             logger.Logger
+
+	// parsed rules and limiter, swapped when the configuration changes so that sampling doesn't need any lock
+	parsedRules atomic.Pointer[parsedSamplingRules]
+	limiter     atomic.Pointer[tracesLimiter]
+}
+
+// parsedSamplingRules are the rules parsed from their raw configuration
+type parsedSamplingRules struct {
+	raw   string
+	rules []traceSamplingRule
+}
+
+// tracesLimiter limits the traces sampled to perSecond
+type tracesLimiter struct {
+	perSecond int
+	limiter   *rate.Limiter
+}
+
+// newTraceSampler creates a new trace sampler.
+// rules is a JSON array of traceSamplingRule, e.g. [{"tags":{"workspaceId":"ws1"},"samplingRate":1}]
+func newTraceSampler(
+	samplingRate config.ValueLoader[float64],
+	rules config.ValueLoader[string],
+	maxTracesPerSecond config.ValueLoader[int],
+	log logger.Logger,
+) *traceSampler {
+	return &traceSampler{
+		samplingRate:       samplingRate,
+		rules:              rules,
+		maxTracesPerSecond: maxTracesPerSecond,
+		logger:             log,
+	}
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s *traceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	psc := trace.SpanContextFromContext(p.ParentContext)
+	result := sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: psc.TraceState()}
+
+	if psc.IsValid() && psc.IsSampled() {
+		result.Decision = sdktrace.RecordAndSample
+		return result
+	}
+
+	samplingRate, matched := s.ruleSamplingRate(p)
+	if !matched {
+		if psc.IsValid() { // parent not sampled and no rule to override its decision
+			return result
+		}
+		samplingRate = s.samplingRate.Load()
+	}
+
+	if sampleTraceID(p.TraceID, samplingRate) && s.allow() {
+		result.Decision = sdktrace.RecordAndSample
+	}
+	return result
+}
+
+// Description implements sdktrace.Sampler
+func (s *traceSampler) Description() string {
+	return fmt.Sprintf("TraceSampler{rate:%g,rules:%s,maxTracesPerSecond:%d}",
+		s.samplingRate.Load(), s.rules.Load(), s.maxTracesPerSecond.Load(),
+	)
+}
+
+// ruleSamplingRate returns the sampling rate of the first rule matching the span, if any
+func (s *traceSampler) ruleSamplingRate(p sdktrace.SamplingParameters) (float64, bool) {
+	for _, rule := range s.getRules() {
+		if rule.matches(p) {
+			return rule.SamplingRate, true
+		}
+	}
+	return 0, false
+}
+
+// getRules returns the parsed rules, parsing them again only if they changed
+func (s *traceSampler) getRules() []traceSamplingRule {
+	rawRules := s.rules.Load()
+	current := s.parsedRules.Load()
+	if current != nil && current.raw == rawRules {
+		return current.rules
+	}
+
+	parsed := &parsedSamplingRules{raw: rawRules}
+	var err error
+	if rawRules != "" {
+		if err = json.Unmarshal([]byte(rawRules), &parsed.rules); err != nil {
+			parsed.rules = nil
+		}
+	}
+	if s.parsedRules.CompareAndSwap(current, parsed) && err != nil { // warning once per change
+		s.logger.Warnf("invalid trace sampling rules %q, ignoring them: %v", rawRules, err)
+	}
+	return parsed.rules
+}
+
+// allow returns false if the maximum number of traces per second has been reached
+func (s *traceSampler) allow() bool {
+	maxTracesPerSecond := s.maxTracesPerSecond.Load()
+	if maxTracesPerSecond <= 0 {
+		return true
+	}
+
+	l := s.limiter.Load()
+	if l == nil || l.perSecond != maxTracesPerSecond {
+		newLimiter := &tracesLimiter{
+			perSecond: maxTracesPerSecond,
+			limiter:   rate.NewLimiter(rate.Limit(maxTracesPerSecond), maxTracesPerSecond),
+		}
+		if !s.limiter.CompareAndSwap(l, newLimiter) {
+			return s.allow() // swapped concurrently
+		}
+		l = newLimiter
+	}
+	return l.limiter.Allow()
+}
+
+// sampleTraceID deterministically samples the given ratio of trace IDs, the same way sdktrace.TraceIDRatioBased does
+func sampleTraceID(traceID trace.TraceID, samplingRate float64) bool {
+	if samplingRate >= 1 {
+		return true
+	}
+	if samplingRate <= 0 {
+		return false
+	}
+	x := binary.BigEndian.Uint64(traceID[8:16]) >> 1
+	return x < uint64(samplingRate*(1<<63))
+}
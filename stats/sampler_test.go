@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/khulnasoft/go-kit/config"
+	"github.com/khulnasoft/go-kit/logger"
+)
+
+func TestTraceSampler(t *testing.T) {
+	var (
+		lowTraceID  = trace.TraceID{0x01} // sampled with any positive sampling rate
+		highTraceID = trace.TraceID{0x01, 8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff}
+		parentCtx   = func(sampled bool) context.Context {
+			sc := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: lowTraceID, SpanID: trace.SpanID{0x01}, Remote: true,
+			})
+			if sampled {
+				sc = sc.WithTraceFlags(trace.FlagsSampled)
+			}
+			return trace.ContextWithSpanContext(context.Background(), sc)
+		}
+		params = func(ctx context.Context, traceID trace.TraceID, name string, attrs ...attribute.KeyValue) sdktrace.SamplingParameters {
+			return sdktrace.SamplingParameters{ParentContext: ctx, TraceID: traceID, Name: name, Attributes: attrs}
+		}
+		newSampler = func(samplingRate float64, rules string, maxTracesPerSecond int) *traceSampler {
+			return newTraceSampler(
+				config.SingleValueLoader(samplingRate),
+				config.SingleValueLoader(rules),
+				config.SingleValueLoader(maxTracesPerSecond),
+				logger.NOP,
+			)
+		}
+	)
+
+	t.Run("default sampling rate", func(t *testing.T) {
+		s := newSampler(0.5, "", 0)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), highTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.Drop, newSampler(0, "", 0).ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.RecordAndSample, newSampler(1, "", 0).ShouldSample(params(context.Background(), highTraceID, "span")).Decision)
+	})
+
+	t.Run("parent based", func(t *testing.T) {
+		s := newSampler(0, "", 0)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(parentCtx(true), lowTraceID, "span")).Decision)
+
+		s = newSampler(1, "", 0)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(parentCtx(false), lowTraceID, "span")).Decision)
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		s := newSampler(0, `[
+			{"spanName":"process","tags":{"workspaceId":"ws1"},"samplingRate":1},
+			{"spanName":"process","samplingRate":0.5},
+			{"tags":{"workspaceId":"ws2"},"samplingRate":1}
+		]`, 0)
+
+		ws1 := attribute.String("workspaceId", "ws1")
+		ws2 := attribute.String("workspaceId", "ws2")
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), highTraceID, "process", ws1)).Decision)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), highTraceID, "process", ws2)).Decision, "first matching rule wins")
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "process")).Decision)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), highTraceID, "other", ws2)).Decision)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), lowTraceID, "other", ws1)).Decision, "no rule matches")
+
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(parentCtx(false), lowTraceID, "other", ws2)).Decision,
+			"a matching rule overrides a not sampled parent",
+		)
+	})
+
+	t.Run("invalid rules are ignored", func(t *testing.T) {
+		s := newSampler(1, `[{"samplingRate":`, 0)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), highTraceID, "span")).Decision)
+	})
+
+	t.Run("max traces per second", func(t *testing.T) {
+		s := newSampler(1, "", 2)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(parentCtx(true), lowTraceID, "span")).Decision,
+			"children of sampled spans are not rate limited",
+		)
+	})
+
+	t.Run("hot reload", func(t *testing.T) {
+		c := config.New()
+		s := newTraceSampler(
+			c.GetReloadableFloat64Var(0, "OpenTelemetry.traces.samplingRate"),
+			c.GetReloadableStringVar("", "OpenTelemetry.traces.sampling.rules"),
+			c.GetReloadableIntVar(0, 1, "OpenTelemetry.traces.sampling.maxTracesPerSecond"),
+			logger.NOP,
+		)
+		ws1 := attribute.String("workspaceId", "ws1")
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), lowTraceID, "span", ws1)).Decision)
+
+		c.Set("OpenTelemetry.traces.samplingRate", 1.0)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "span", ws1)).Decision)
+
+		c.Set("OpenTelemetry.traces.sampling.rules", `[{"tags":{"workspaceId":"ws1"},"samplingRate":0}]`)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), lowTraceID, "span", ws1)).Decision)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+
+		c.Set("OpenTelemetry.traces.sampling.maxTracesPerSecond", 1)
+		require.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+		require.Equal(t, sdktrace.Drop, s.ShouldSample(params(context.Background(), lowTraceID, "span")).Decision)
+	})
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithTags returns a Stats that adds the provided tags to every measurement it creates.
+// Tags provided when creating a measurement take precedence over the base ones.
+func WithTags(s Stats, tags Tags) Stats {
+	return Scope(s, "", tags)
+}
+
+// Scope returns a Stats that prefixes the name of every measurement it creates with prefix followed by an
+// underscore (e.g. "router" and "jobs_total" become "router_jobs_total") and adds the provided tags to it.
+// Tags provided when creating a measurement take precedence over the base ones.
+// Scopes can be nested, in which case prefixes are concatenated and tags are merged.
+// The returned Stats can be used with any Stats implementation (statsd, otel, memstats, nop...).
+func Scope(s Stats, prefix string, tags Tags) Stats {
+	if parent, ok := s.(*scopedStats); ok {
+		return &scopedStats{
+			Stats:  parent.Stats,
+			prefix: parent.name(prefix),
+			tags:   mergeTags(parent.tags, tags),
+		}
+	}
+	return &scopedStats{
+		Stats:  s,
+		prefix: prefix,
+		tags:   mergeTags(nil, tags),
+	}
+}
+
+// scopedStats wraps a Stats adding a name prefix and base tags to all its measurements.
+// Start, Stop and NewTracer are delegated to the wrapped Stats.
+type scopedStats struct {
+	Stats
+	prefix string
+	tags   Tags
+}
+
+// NewStat creates a new Measurement with provided Name and Type
+func (s *scopedStats) NewStat(name, statType string) Measurement {
+	return s.Stats.NewTaggedStat(s.name(name), statType, mergeTags(s.tags, nil))
+}
+
+// NewTaggedStat creates a new Measurement with provided Name, Type and Tags
+func (s *scopedStats) NewTaggedStat(name, statType string, tags Tags) Measurement {
+	return s.Stats.NewTaggedStat(s.name(name), statType, mergeTags(s.tags, tags))
+}
+
+// NewSampledTaggedStat creates a new Measurement with provided Name, Type and Tags
+// Deprecated: use NewTaggedStat instead
+func (s *scopedStats) NewSampledTaggedStat(name, statType string, tags Tags) Measurement {
+	return s.Stats.NewSampledTaggedStat(s.name(name), statType, mergeTags(s.tags, tags))
+}
+
+// RegisterCollector registers a collector whose gauges are prefixed and tagged like the ones of this scope.
+func (s *scopedStats) RegisterCollector(c Collector) error {
+	return s.Stats.RegisterCollector(&scopedCollector{Collector: c, scope: s})
+}
+
+// Scope returns a nested scope, see Scope
+func (s *scopedStats) Scope(prefix string, tags Tags) Stats {
+	return Scope(s, prefix, tags)
+}
+
+func (s *scopedStats) name(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	if name == "" {
+		return s.prefix
+	}
+	return s.prefix + "_" + name
+}
+
+// mergeTags returns a new Tags containing the base tags overridden by the provided ones.
+func mergeTags(base, tags Tags) Tags {
+	if len(base) == 0 && len(tags) == 0 {
+		return nil
+	}
+	merged := make(Tags, len(base)+len(tags))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
+
+// scopedCollector is a Collector that applies the prefix and base tags of a scope to the gauges of the
+// wrapped collector.
+type scopedCollector struct {
+	Collector
+	scope *scopedStats
+}
+
+func (c *scopedCollector) Collect(gaugeFunc gaugeTagsFunc) {
+	c.Collector.Collect(c.wrap(gaugeFunc))
+}
+
+func (c *scopedCollector) Zero(gaugeFunc gaugeTagsFunc) {
+	c.Collector.Zero(c.wrap(gaugeFunc))
+}
+
+// ID returns the ID of the wrapped collector, prefixed by the scope prefix if any and followed by the sorted scope
+// tags if any, so that the same collector can be registered by different scopes.
+func (c *scopedCollector) ID() string {
+	id := c.scope.name(c.Collector.ID())
+	if len(c.scope.tags) == 0 {
+		return id
+	}
+	keys := make([]string, 0, len(c.scope.tags))
+	for k := range c.scope.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + c.scope.tags[k]
+	}
+	return id + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (c *scopedCollector) wrap(gaugeFunc gaugeTagsFunc) gaugeTagsFunc {
+	return func(key string, tags Tags, val uint64) {
+		gaugeFunc(c.scope.name(key), mergeTags(c.scope.tags, tags), val)
+	}
+}
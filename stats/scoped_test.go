@@ -0,0 +1,101 @@
+package stats_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft/go-kit/stats"
+	"github.com/khulnasoft/go-kit/stats/collectors"
+	"github.com/khulnasoft/go-kit/stats/memstats"
+)
+
+func TestScope(t *testing.T) {
+	t.Run("with tags", func(t *testing.T) {
+		store, err := memstats.New()
+		require.NoError(t, err)
+
+		s := stats.WithTags(store, stats.Tags{"module": "router", "destType": "WEBHOOK"})
+		s.NewStat("jobs", stats.CountType).Increment()
+		s.NewTaggedStat("jobs", stats.CountType, stats.Tags{"destType": "S3", "workspaceId": "ws"}).Count(2)
+
+		require.EqualValues(t, 1, store.Get("jobs", stats.Tags{"module": "router", "destType": "WEBHOOK"}).LastValue())
+		require.EqualValues(t, 2, store.Get("jobs", stats.Tags{"module": "router", "destType": "S3", "workspaceId": "ws"}).LastValue())
+	})
+
+	t.Run("prefix and nesting", func(t *testing.T) {
+		store, err := memstats.New()
+		require.NoError(t, err)
+
+		tags := stats.Tags{"module": "router"}
+		router := stats.Scope(store, "router", tags)
+		batch := stats.Scope(router, "batch", stats.Tags{"destType": "S3"})
+		router.NewStat("jobs", stats.CountType).Increment()
+		batch.NewSampledTaggedStat("size", stats.HistogramType, stats.Tags{"workspaceId": "ws"}).Observe(3)
+		stats.Scope(router, "", nil).NewStat("events", stats.GaugeType).Gauge(5)
+
+		require.EqualValues(t, 1, store.Get("router_jobs", stats.Tags{"module": "router"}).LastValue())
+		require.EqualValues(t, []float64{3}, store.Get("router_batch_size", stats.Tags{
+			"module": "router", "destType": "S3", "workspaceId": "ws",
+		}).Values())
+		require.EqualValues(t, 5, store.Get("router_events", stats.Tags{"module": "router"}).LastValue())
+		require.Equal(t, stats.Tags{"module": "router"}, tags, "base tags should not be modified")
+	})
+
+	t.Run("method", func(t *testing.T) {
+		store, err := memstats.New()
+		require.NoError(t, err)
+
+		batch := store.Scope("router", stats.Tags{"module": "router"}).Scope("batch", stats.Tags{"destType": "S3"})
+		batch.NewStat("jobs", stats.CountType).Increment()
+
+		require.EqualValues(t, 1, store.Get("router_batch_jobs", stats.Tags{"module": "router", "destType": "S3"}).LastValue())
+		require.Equal(t, stats.NOP, stats.NOP.Scope("router", stats.Tags{"module": "router"}))
+	})
+
+	t.Run("collectors", func(t *testing.T) {
+		store, err := memstats.New()
+		require.NoError(t, err)
+
+		c := collectors.NewStaticMetric("version", stats.Tags{"v": "1"}, 1)
+		require.NoError(t, stats.Scope(store, "router", stats.Tags{"module": "router"}).RegisterCollector(c))
+		require.NoError(t, stats.Scope(store, "proc", stats.Tags{"module": "proc"}).RegisterCollector(c))
+
+		require.EqualValues(t, 1, store.Get("router_version", stats.Tags{"module": "router", "v": "1"}).LastValue())
+		require.EqualValues(t, 1, store.Get("proc_version", stats.Tags{"module": "proc", "v": "1"}).LastValue())
+	})
+
+	t.Run("collector ids", func(t *testing.T) {
+		s := &collectorIDs{Stats: stats.NOP}
+		c := collectors.NewStaticMetric("version", nil, 1)
+		require.NoError(t, s.RegisterCollector(c))
+		require.NoError(t, stats.WithTags(s, stats.Tags{"module": "router", "db": "jobs"}).RegisterCollector(c))
+		require.NoError(t, stats.Scope(s, "router", nil).RegisterCollector(c))
+		require.NoError(t, stats.Scope(s, "router", stats.Tags{"module": "router"}).RegisterCollector(c))
+		require.Equal(t, []string{
+			c.ID(),
+			c.ID() + "{db=jobs,module=router}",
+			"router_" + c.ID(),
+			"router_" + c.ID() + "{module=router}",
+		}, s.ids, "collectors registered by different scopes have different ids")
+	})
+
+	t.Run("nop", func(t *testing.T) {
+		s := stats.Scope(stats.NOP, "router", stats.Tags{"module": "router"})
+		require.NotPanics(t, func() {
+			s.NewTaggedStat("jobs", stats.CountType, stats.Tags{"a": "b"}).Increment()
+			s.NewStat("latency", stats.TimerType).RecordDuration()()
+		})
+	})
+}
+
+// collectorIDs is a Stats recording the ids of the collectors registered
+type collectorIDs struct {
+	stats.Stats
+	ids []string
+}
+
+func (s *collectorIDs) RegisterCollector(c stats.Collector) error {
+	s.ids = append(s.ids, c.ID())
+	return nil
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"os"
 	"strings"
-	"sync/atomic"
 	"time"
 	"unicode"
 
@@ -62,6 +61,10 @@ type Stats interface {
 	// RegisterCollector registers a collector that will collect stats periodically.
 	// You can find available collectors in the stats/collectors package.
 	RegisterCollector(c Collector) error
+
+	// Scope returns a Stats which prefixes the names of the measurements it creates and adds the provided tags to
+	// them, see Scope
+	Scope(prefix string, tags Tags) Stats
 }
 
 type loggerFactory interface {
@@ -72,25 +75,18 @@ type loggerFactory interface {
 func NewStats(
 	config *config.Config, loggerFactory loggerFactory, metricManager svcMetric.Manager, opts ...Option,
 ) Stats {
-	excludedTags := make(map[string]struct{})
-	excludedTagsSlice := config.GetStringSlice("statsExcludedTags", nil)
-	for _, tag := range excludedTagsSlice {
-		excludedTags[tag] = struct{}{}
-	}
-
-	enabled := atomic.Bool{}
-	enabled.Store(config.GetBool("enableStats", true))
 	statsConfig := statsConfig{
-		excludedTags:        excludedTags,
-		enabled:             &enabled,
+		enabled:             config.GetReloadableBoolVar(true, "enableStats"),
+		excludedTags:        config.GetReloadableStringSliceVar(nil, "statsExcludedTags"),
 		instanceName:        config.GetString("INSTANCE_ID", ""),
 		namespaceIdentifier: os.Getenv("KUBE_NAMESPACE"),
 		periodicStatsConfig: periodicStatsConfig{
 			enabled:                 config.GetBool("RuntimeStats.enabled", true),
 			statsCollectionInterval: config.GetInt64("RuntimeStats.statsCollectionInterval", 10),
-			enableCPUStats:          config.GetBool("RuntimeStats.enableCPUStats", true),
-			enableMemStats:          config.GetBool("RuntimeStats.enabledMemStats", true),
-			enableGCStats:           config.GetBool("RuntimeStats.enableGCStats", true),
+			enableCPUStats:          config.GetReloadableBoolVar(true, "RuntimeStats.enableCPUStats"),
+			enableMemStats:          config.GetReloadableBoolVar(true, "RuntimeStats.enabledMemStats"),
+			enableGCStats:           config.GetReloadableBoolVar(true, "RuntimeStats.enableGCStats"),
+			useRuntimeMetrics:       config.GetBool("RuntimeStats.useRuntimeMetrics", false),
 			metricManager:           metricManager,
 		},
 	}
@@ -116,14 +112,25 @@ func NewStats(
 			prometheusGatherer:       gatherer,
 			tracerProvider:           noop.NewTracerProvider(),
 			otelConfig: otelStatsConfig{
-				tracesEndpoint:           config.GetString("OpenTelemetry.traces.endpoint", ""),
-				tracingSamplingRate:      config.GetFloat64("OpenTelemetry.traces.samplingRate", 0.1),
-				withTracingSyncer:        config.GetBool("OpenTelemetry.traces.withSyncer", false),
-				withZipkin:               config.GetBool("OpenTelemetry.traces.withZipkin", false),
-				metricsEndpoint:          config.GetString("OpenTelemetry.metrics.endpoint", ""),
-				metricsExportInterval:    config.GetDuration("OpenTelemetry.metrics.exportInterval", 5, time.Second),
-				enablePrometheusExporter: config.GetBool("OpenTelemetry.metrics.prometheus.enabled", false),
-				prometheusMetricsPort:    config.GetInt("OpenTelemetry.metrics.prometheus.port", 0),
+				tracesEndpoint:              config.GetString("OpenTelemetry.traces.endpoint", ""),
+				tracingSamplingRate:         config.GetReloadableFloat64Var(0.1, "OpenTelemetry.traces.samplingRate"),
+				tracingSamplingRules:        config.GetReloadableStringVar("", "OpenTelemetry.traces.sampling.rules"),
+				tracingMaxTracesPerSecond:   config.GetReloadableIntVar(0, 1, "OpenTelemetry.traces.sampling.maxTracesPerSecond"),
+				withTracingSyncer:           config.GetBool("OpenTelemetry.traces.withSyncer", false),
+				withZipkin:                  config.GetBool("OpenTelemetry.traces.withZipkin", false),
+				metricsEndpoint:             config.GetString("OpenTelemetry.metrics.endpoint", ""),
+				metricsExportInterval:       config.GetDuration("OpenTelemetry.metrics.exportInterval", 5, time.Second),
+				enablePrometheusExporter:    config.GetBool("OpenTelemetry.metrics.prometheus.enabled", false),
+				prometheusMetricsPort:       config.GetInt("OpenTelemetry.metrics.prometheus.port", 0),
+				enablePrometheusOpenMetrics: config.GetBool("OpenTelemetry.metrics.prometheus.openMetrics", false),
+				tracesExporter:              config.GetString("OpenTelemetry.traces.exporter", ""),
+				tracesFilePath:              config.GetString("OpenTelemetry.traces.file.path", "otel-traces.jsonl"),
+				metricsExporter:             config.GetString("OpenTelemetry.metrics.exporter", ""),
+				metricsFilePath:             config.GetString("OpenTelemetry.metrics.file.path", "otel-metrics.jsonl"),
+				fileMaxSizeMB:               config.GetInt("OpenTelemetry.file.maxSizeMB", 100),
+				fileMaxBackups:              config.GetInt("OpenTelemetry.file.maxBackups", 5),
+				observeMetricRegistry:       config.GetBool("OpenTelemetry.metrics.registry.observable", false),
+				metricRegistryStaleTTL:      config.GetDuration("OpenTelemetry.metrics.registry.staleTTL", 0, time.Second),
 			},
 			collectorAggregator: &aggregatedCollector{},
 		}
@@ -140,7 +147,7 @@ func NewStats(
 		statsdConfig: statsdConfig{
 			tagsFormat:          config.GetString("statsTagsFormat", "influxdb"),
 			statsdServerURL:     config.GetString("STATSD_SERVER_URL", "localhost:8125"),
-			samplingRate:        float32(config.GetFloat64("statsSamplingRate", 1)),
+			samplingRate:        config.GetReloadableFloat64Var(1, "statsSamplingRate"),
 			instanceName:        statsConfig.instanceName,
 			namespaceIdentifier: statsConfig.namespaceIdentifier,
 		},
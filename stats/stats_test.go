@@ -55,3 +55,21 @@ func TestUnstartedShouldNotPanicWhileTracing(t *testing.T) {
 		span.End()
 	})
 }
+
+func TestBaggage(t *testing.T) {
+	ctx, err := SetBaggageValue(context.Background(), "workspaceId", "ws1")
+	require.NoError(t, err)
+	ctx, err = SetBaggageValue(ctx, "sourceId", "src 1")
+	require.NoError(t, err)
+	require.Equal(t, "ws1", GetBaggageValue(ctx, "workspaceId"))
+	require.Empty(t, GetBaggageValue(ctx, "missing"))
+
+	header := GetBaggageFromContext(ctx)
+	newCtx := InjectBaggageIntoContext(context.Background(), header)
+	require.Equal(t, "ws1", GetBaggageValue(newCtx, "workspaceId"))
+	require.Equal(t, "src 1", GetBaggageValue(newCtx, "sourceId"))
+
+	_, err = SetBaggageValue(ctx, "", "value")
+	require.Error(t, err)
+	require.Equal(t, ctx, InjectBaggageIntoContext(ctx, "invalid,,header="))
+}
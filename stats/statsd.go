@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/alexcesaro/statsd.v2"
 
+	"github.com/khulnasoft/go-kit/config"
 	"github.com/khulnasoft/go-kit/logger"
 )
 
@@ -23,6 +26,8 @@ type statsdStats struct {
 	logger                     logger.Logger
 	backgroundCollectionCtx    context.Context
 	backgroundCollectionCancel func()
+	// started is true if the service was started with stats enabled
+	started atomic.Bool
 
 	// tracing not supported when using stats with StatsD
 	tracer trace.Tracer
@@ -58,7 +63,6 @@ func (s *statsdStats) Start(ctx context.Context, goFactory GoRoutineFactory) err
 				s.statsdConfig.statsdDefaultTags(),
 			)
 			if err != nil {
-				s.config.enabled.Store(false)
 				s.logger.Errorf("error while creating new StatsD client, giving up: %v", err)
 			} else {
 				s.state.clientsLock.Lock()
@@ -86,6 +90,7 @@ func (s *statsdStats) Start(ctx context.Context, goFactory GoRoutineFactory) err
 		}
 	})
 
+	s.started.Store(true)
 	s.logger.Infof("Stats started successfully in mode %q with address %q", "StatsD", s.statsdConfig.statsdServerURL)
 
 	return nil
@@ -117,11 +122,13 @@ func (s *statsdStats) collectPeriodicStats(goFactory GoRoutineFactory) {
 	gaugeFunc := func(key string, val uint64) {
 		s.NewStat("runtime_"+key, GaugeType).Gauge(val)
 	}
-	s.state.rc = newRuntimeStatsCollector(gaugeFunc)
-	s.state.rc.PauseDur = time.Duration(s.config.periodicStatsConfig.statsCollectionInterval) * time.Second
-	s.state.rc.EnableCPU = s.config.periodicStatsConfig.enableCPUStats
-	s.state.rc.EnableMem = s.config.periodicStatsConfig.enableMemStats
-	s.state.rc.EnableGC = s.config.periodicStatsConfig.enableGCStats
+	if !s.config.periodicStatsConfig.useRuntimeMetrics {
+		s.state.rc = newRuntimeStatsCollector(gaugeFunc)
+		s.state.rc.PauseDur = time.Duration(s.config.periodicStatsConfig.statsCollectionInterval) * time.Second
+		s.state.rc.EnableCPU = s.config.periodicStatsConfig.enableCPUStats
+		s.state.rc.EnableMem = s.config.periodicStatsConfig.enableMemStats
+		s.state.rc.EnableGC = s.config.periodicStatsConfig.enableGCStats
+	}
 	s.state.mc = newMetricStatsCollector(s, s.config.periodicStatsConfig.metricManager)
 
 	gaugeTagsFunc := func(key string, tags Tags, val uint64) {
@@ -129,24 +136,47 @@ func (s *statsdStats) collectPeriodicStats(goFactory GoRoutineFactory) {
 	}
 	s.state.ac.gaugeFunc = gaugeTagsFunc
 	s.state.ac.PauseDur = time.Duration(s.config.periodicStatsConfig.statsCollectionInterval) * time.Second
+	if !s.config.disableBuildInfo {
+		if err := s.state.ac.Add(newBuildInfoCollector(s.config.serviceName, s.config.serviceVersion)); err != nil {
+			s.logger.Errorf("failed to register build info collector: %v", err)
+		}
+	}
 
+	var wg sync.WaitGroup
 	if s.config.periodicStatsConfig.enabled {
-		var wg sync.WaitGroup
-		wg.Add(3)
-		goFactory.Go(func() {
-			defer wg.Done()
-			s.state.rc.run(s.backgroundCollectionCtx)
-		})
+		if s.config.periodicStatsConfig.useRuntimeMetrics {
+			if err := s.state.ac.Add(s.config.periodicStatsConfig.newRuntimeMetricsCollector()); err != nil {
+				s.logger.Errorf("failed to register runtime metrics collector: %v", err)
+			}
+		} else {
+			wg.Add(1)
+			goFactory.Go(func() {
+				defer wg.Done()
+				s.state.rc.run(s.backgroundCollectionCtx)
+			})
+		}
+		wg.Add(1)
 		goFactory.Go(func() {
 			defer wg.Done()
 			s.state.mc.run(s.backgroundCollectionCtx)
 		})
+	}
+	if s.config.periodicStatsConfig.enabled {
+		wg.Add(1)
 		goFactory.Go(func() {
 			defer wg.Done()
 			s.state.ac.Run(s.backgroundCollectionCtx)
 		})
-		wg.Wait()
+	} else {
+		// if periodic stats are disabled, the aggregated collector only runs once there is something to collect,
+		// i.e. the build info or the collectors registered by the application, possibly later on
+		s.state.ac.runOnceAdded(func() {
+			goFactory.Go(func() {
+				s.state.ac.Run(s.backgroundCollectionCtx)
+			})
+		})
 	}
+	wg.Wait()
 }
 
 func (s *statsdStats) RegisterCollector(c Collector) error {
@@ -155,7 +185,7 @@ func (s *statsdStats) RegisterCollector(c Collector) error {
 
 // Stop stops periodic collection of stats.
 func (s *statsdStats) Stop() {
-	if !s.config.enabled.Load() || !s.state.connEstablished {
+	if !s.started.Load() || !s.state.connEstablished {
 		return
 	}
 
@@ -174,42 +204,57 @@ func (s *statsdStats) Stop() {
 
 // NewStat creates a new Measurement with provided Name and Type
 func (s *statsdStats) NewStat(name, statType string) (m Measurement) {
-	return s.internalNewTaggedStat(name, statType, nil, 1)
+	return s.internalNewTaggedStat(name, statType, nil, nil)
 }
 
 func (s *statsdStats) NewTaggedStat(Name, StatType string, tags Tags) (m Measurement) {
-	return s.internalNewTaggedStat(Name, StatType, tags, 1)
+	return s.internalNewTaggedStat(Name, StatType, tags, nil)
 }
 
 func (s *statsdStats) NewSampledTaggedStat(Name, StatType string, tags Tags) (m Measurement) {
 	return s.internalNewTaggedStat(Name, StatType, tags, s.statsdConfig.samplingRate)
 }
 
-func (s *statsdStats) internalNewTaggedStat(name, statType string, tags Tags, samplingRate float32) (m Measurement) {
-	// If stats is not enabled, returning a dummy struct
-	if !s.config.enabled.Load() {
+// Scope returns a Stats which prefixes the names of the measurements it creates and adds the provided tags to them
+func (s *statsdStats) Scope(prefix string, tags Tags) Stats {
+	return Scope(s, prefix, tags)
+}
+
+// internalNewTaggedStat creates a new measurement. If samplingRate is nil, the measurement is not sampled,
+// otherwise its sampling rate follows the provided loader.
+func (s *statsdStats) internalNewTaggedStat(
+	name, statType string, tags Tags, samplingRate config.ValueLoader[float64],
+) (m Measurement) {
+	// If stats is not enabled, returning a dummy struct.
+	// Measurements created while stats are disabled are still fully functional if the service was started,
+	// so that they start emitting again if stats are re-enabled at runtime.
+	if !s.config.enabled.Load() && !s.started.Load() {
 		return s.newStatsdMeasurement(name, statType, &statsdClient{})
 	}
 
 	// Clean up tags based on deployment type. No need to send workspace id tag for free tier customers.
 	newTags := make(Tags)
+	excludedTags := s.config.loadExcludedTags()
 	for k, v := range tags {
 		if strings.Trim(k, " ") == "" {
 			s.logger.Warnf("removing empty tag key with value %q for measurement %q", v, name)
 			continue
 		}
-		if _, ok := s.config.excludedTags[k]; ok {
+		if slices.Contains(excludedTags, k) {
 			continue
 		}
 		sanitizedKey := sanitizeTagKey(k)
-		if _, ok := s.config.excludedTags[sanitizedKey]; ok {
+		if slices.Contains(excludedTags, sanitizedKey) {
 			continue
 		}
 		newTags[sanitizedKey] = v
 	}
 
-	// key comprises the measurement type plus all tag-value pairs
-	taggedClientKey := newTags.String() + fmt.Sprintf("%f", samplingRate)
+	// key comprises all tag-value pairs plus whether the client is sampled or not
+	taggedClientKey := newTags.String()
+	if samplingRate != nil {
+		taggedClientKey += "|sampled"
+	}
 
 	s.state.clientsLock.RLock()
 	taggedClient, found := s.state.clients[taggedClientKey]
@@ -219,14 +264,17 @@ func (s *statsdStats) internalNewTaggedStat(name, statType string, tags Tags, sa
 		s.state.clientsLock.Lock()
 		if taggedClient, found = s.state.clients[taggedClientKey]; !found { // double check for race
 			tagVals := newTags.Strings()
-			taggedClient = &statsdClient{samplingRate: samplingRate, tags: tagVals}
+			taggedClient = &statsdClient{samplingRate: 1, samplingRateLoader: samplingRate, tags: tagVals}
+			if samplingRate != nil {
+				taggedClient.samplingRate = float32(samplingRate.Load())
+			}
 			if s.state.connEstablished {
 				taggedClient.statsd = s.state.client.statsd.Clone(
 					s.state.conn,
 					s.statsdConfig.statsdTagsFormat(),
 					s.statsdConfig.statsdDefaultTags(),
 					statsd.Tags(tagVals...),
-					statsd.SampleRate(samplingRate),
+					statsd.SampleRate(taggedClient.samplingRate),
 				)
 			} else {
 				// new statsd clients will be created when connection is established for all pending clients
@@ -250,7 +298,7 @@ func (s *statsdStats) newStatsdMeasurement(name, statType string, client *statsd
 		name = "novalue"
 	}
 	baseMeasurement := &statsdMeasurement{
-		enabled:            s.config.enabled.Load(),
+		enabled:            s.config.enabled,
 		name:               name,
 		client:             client,
 		genericMeasurement: genericMeasurement{statType: statType},
@@ -272,7 +320,7 @@ func (s *statsdStats) newStatsdMeasurement(name, statType string, client *statsd
 type statsdConfig struct {
 	tagsFormat          string
 	statsdServerURL     string
-	samplingRate        float32
+	samplingRate        config.ValueLoader[float64]
 	instanceName        string
 	namespaceIdentifier string
 }
@@ -316,11 +364,13 @@ type statsdState struct {
 // We use this wrapper to allow for filling the actual statsd client at a later stage,
 // in case a connection cannot be established immediately at startup.
 type statsdClient struct {
-	samplingRate float32
-	tags         []string
+	tags []string
+	// samplingRateLoader, if not nil, allows the sampling rate of the client to be changed at runtime
+	samplingRateLoader config.ValueLoader[float64]
 
-	statsdMu sync.RWMutex // protects the following
-	statsd   *statsd.Client
+	statsdMu     sync.RWMutex // protects the following
+	samplingRate float32
+	statsd       *statsd.Client
 }
 
 // ready returns true if the statsd client is ready to be used (not nil).
@@ -329,3 +379,26 @@ type statsdClient struct {
 func (sc *statsdClient) ready() bool {
 	return sc.statsd != nil
 }
+
+// updateSamplingRate replaces the underlying statsd client with a clone using the new sampling rate,
+// if the latter has been changed at runtime.
+func (sc *statsdClient) updateSamplingRate() {
+	if sc.samplingRateLoader == nil {
+		return
+	}
+	samplingRate := float32(sc.samplingRateLoader.Load())
+
+	sc.statsdMu.RLock()
+	changed := sc.ready() && sc.samplingRate != samplingRate
+	sc.statsdMu.RUnlock()
+	if !changed {
+		return
+	}
+
+	sc.statsdMu.Lock()
+	defer sc.statsdMu.Unlock()
+	if sc.ready() && sc.samplingRate != samplingRate { // double check for race
+		sc.statsd = sc.statsd.Clone(statsd.SampleRate(samplingRate))
+		sc.samplingRate = samplingRate
+	}
+}
@@ -1,24 +1,33 @@
 package stats
 
 import (
+	"context"
 	"time"
 
 	"gopkg.in/alexcesaro/statsd.v2"
+
+	"github.com/khulnasoft/go-kit/config"
 )
 
 // statsdMeasurement is the statsd-specific implementation of Measurement
 type statsdMeasurement struct {
 	genericMeasurement
-	enabled bool
+	enabled config.ValueLoader[bool]
 	name    string
 	client  *statsdClient
 }
 
+// rLock applies any sampling rate change to the client and then acquires m.client.statsdMu.RLock
+func (m *statsdMeasurement) rLock() {
+	m.client.updateSamplingRate()
+	m.client.statsdMu.RLock()
+}
+
 // skip returns true if the stat should be skipped (stats disabled or client not ready)
 //
 // m.client.statsdMu.RLock should be held when calling this method.
 func (m *statsdMeasurement) skip() bool {
-	return !m.enabled || !m.client.ready()
+	return !m.enabled.Load() || !m.client.ready()
 }
 
 // statsdCounter represents a counter stat
@@ -27,7 +36,7 @@ type statsdCounter struct {
 }
 
 func (c *statsdCounter) Count(n int) {
-	c.client.statsdMu.RLock()
+	c.rLock()
 	defer c.client.statsdMu.RUnlock()
 	if c.skip() {
 		return
@@ -37,7 +46,7 @@ func (c *statsdCounter) Count(n int) {
 
 // Increment increases the stat by 1. Is the Equivalent of Count(1). Only applies to CountType stats
 func (c *statsdCounter) Increment() {
-	c.client.statsdMu.RLock()
+	c.rLock()
 	defer c.client.statsdMu.RUnlock()
 	if c.skip() {
 		return
@@ -52,7 +61,7 @@ type statsdGauge struct {
 
 // Gauge records an absolute value for this stat. Only applies to GaugeType stats
 func (g *statsdGauge) Gauge(value interface{}) {
-	g.client.statsdMu.RLock()
+	g.rLock()
 	defer g.client.statsdMu.RUnlock()
 	if g.skip() {
 		return
@@ -69,7 +78,7 @@ type statsdTimer struct {
 // Start starts a new timing for this stat. Only applies to TimerType stats
 // Deprecated: Use concurrent safe SendTiming() instead
 func (t *statsdTimer) Start() {
-	t.client.statsdMu.RLock()
+	t.rLock()
 	defer t.client.statsdMu.RUnlock()
 	if t.skip() {
 		return
@@ -81,7 +90,7 @@ func (t *statsdTimer) Start() {
 // End send the time elapsed since the Start()  call of this stat. Only applies to TimerType stats
 // Deprecated: Use concurrent safe SendTiming() instead
 func (t *statsdTimer) End() {
-	t.client.statsdMu.RLock()
+	t.rLock()
 	defer t.client.statsdMu.RUnlock()
 	if t.skip() || t.timing == nil {
 		return
@@ -94,9 +103,14 @@ func (t *statsdTimer) Since(start time.Time) {
 	t.SendTiming(time.Since(start))
 }
 
+// SinceCtx is the same as Since since statsd does not support exemplars. Only applies to TimerType stats
+func (t *statsdTimer) SinceCtx(_ context.Context, start time.Time) {
+	t.Since(start)
+}
+
 // SendTiming sends a timing for this stat. Only applies to TimerType stats
 func (t *statsdTimer) SendTiming(duration time.Duration) {
-	t.client.statsdMu.RLock()
+	t.rLock()
 	defer t.client.statsdMu.RUnlock()
 	if t.skip() {
 		return
@@ -104,6 +118,11 @@ func (t *statsdTimer) SendTiming(duration time.Duration) {
 	t.client.statsd.Timing(t.name, int(duration/time.Millisecond))
 }
 
+// SendTimingCtx is the same as SendTiming since statsd does not support exemplars. Only applies to TimerType stats
+func (t *statsdTimer) SendTimingCtx(_ context.Context, duration time.Duration) {
+	t.SendTiming(duration)
+}
+
 // RecordDuration records the duration of time between
 // the call to this function and the execution of the function it returns.
 // Only applies to TimerType stats
@@ -121,10 +140,15 @@ type statsdHistogram struct {
 
 // Observe sends an observation
 func (h *statsdHistogram) Observe(value float64) {
-	h.client.statsdMu.RLock()
+	h.rLock()
 	defer h.client.statsdMu.RUnlock()
 	if h.skip() {
 		return
 	}
 	h.client.statsd.Histogram(h.name, value)
 }
+
+// ObserveCtx is the same as Observe since statsd does not support exemplars
+func (h *statsdHistogram) ObserveCtx(_ context.Context, value float64) {
+	h.Observe(value)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -120,7 +121,7 @@ func TestStatsdMeasurementOperations(t *testing.T) {
 
 	l := logger.NewFactory(c)
 	m := metric.NewManager()
-	s := stats.NewStats(c, l, m)
+	s := stats.NewStats(c, l, m, stats.WithoutBuildInfo())
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -262,7 +263,7 @@ func TestStatsdPeriodicStats(t *testing.T) {
 		prepareFunc(c, m)
 
 		l := logger.NewFactory(c)
-		s := stats.NewStats(c, l, m)
+		s := stats.NewStats(c, l, m, stats.WithoutBuildInfo())
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -394,7 +395,7 @@ func TestStatsdRegisterCollector(t *testing.T) {
 		c.Set("RuntimeStats.enableGCStats", false)
 
 		l := logger.NewFactory(c)
-		s := stats.NewStats(c, l, m)
+		s := stats.NewStats(c, l, m, stats.WithoutBuildInfo())
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -492,6 +493,89 @@ func TestStatsdRegisterCollector(t *testing.T) {
 	})
 }
 
+func TestStatsdCollectorsWithoutPeriodicStats(t *testing.T) {
+	var received []string
+	var receivedMu sync.RWMutex
+	server := newStatsdServer(t, func(s string) {
+		receivedMu.Lock()
+		received = append(received, s)
+		receivedMu.Unlock()
+	})
+	defer server.Close()
+
+	c := config.New()
+	c.Set("STATSD_SERVER_URL", server.addr)
+	c.Set("INSTANCE_ID", "test")
+	c.Set("RuntimeStats.enabled", false)
+
+	l := logger.NewFactory(c)
+	s := stats.NewStats(c, l, metric.NewManager(), stats.WithoutBuildInfo())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.Start(ctx, stats.DefaultGoRoutineFactory))
+	defer s.Stop()
+
+	// the aggregated collector is started by the first collector registered
+	require.NoError(t, s.RegisterCollector(collectors.NewStaticMetric("static", nil, 1)))
+	require.Eventually(t, func() bool {
+		receivedMu.RLock()
+		defer receivedMu.RUnlock()
+		return slices.ContainsFunc(received, func(r string) bool { return strings.HasPrefix(r, "static,") })
+	}, 10*time.Second, time.Millisecond)
+}
+
+func TestStatsdBuildInfo(t *testing.T) {
+	var received []string
+	var receivedMu sync.RWMutex
+	server := newStatsdServer(t, func(s string) {
+		if i := strings.Index(s, ":"); i > 0 {
+			s = s[:i]
+		}
+		receivedMu.Lock()
+		received = append(received, s)
+		receivedMu.Unlock()
+	})
+	defer server.Close()
+
+	c := config.New()
+	t.Setenv("KUBE_NAMESPACE", "my-namespace")
+	c.Set("STATSD_SERVER_URL", server.addr)
+	c.Set("INSTANCE_ID", "test")
+	c.Set("RuntimeStats.enabled", false) // build info is reported even if periodic stats are disabled
+
+	l := logger.NewFactory(c)
+	s := stats.NewStats(c, l, metric.NewManager(), stats.WithServiceName("my-service"), stats.WithServiceVersion("v1.2.3"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.Start(ctx, stats.DefaultGoRoutineFactory))
+	defer s.Stop()
+
+	contains := func(prefix string) bool {
+		receivedMu.RLock()
+		defer receivedMu.RUnlock()
+		for _, r := range received {
+			if strings.HasPrefix(r, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	require.Eventually(t, func() bool {
+		return contains("build_info,") && contains("service_start_time_seconds,") && contains("service_uptime_seconds,")
+	}, 10*time.Second, time.Millisecond)
+
+	receivedMu.RLock()
+	defer receivedMu.RUnlock()
+	for _, r := range received {
+		if strings.HasPrefix(r, "build_info,") {
+			require.Contains(t, r, "serviceName=my-service")
+			require.Contains(t, r, "serviceVersion=v1.2.3")
+		}
+	}
+}
+
 func TestStatsdExcludedTags(t *testing.T) {
 	var lastReceived atomic.Value
 	server := newStatsdServer(t, func(s string) { lastReceived.Store(s) })
@@ -505,7 +589,7 @@ func TestStatsdExcludedTags(t *testing.T) {
 
 	l := logger.NewFactory(c)
 	m := metric.NewManager()
-	s := stats.NewStats(c, l, m)
+	s := stats.NewStats(c, l, m, stats.WithoutBuildInfo())
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -521,6 +605,79 @@ func TestStatsdExcludedTags(t *testing.T) {
 	}, 2*time.Second, time.Millisecond)
 }
 
+func TestStatsdHotReload(t *testing.T) {
+	var lastReceived atomic.Value
+	server := newStatsdServer(t, func(s string) { lastReceived.Store(s) })
+	defer server.Close()
+
+	c := config.New()
+	c.Set("STATSD_SERVER_URL", server.addr)
+	c.Set("INSTANCE_ID", "test")
+	c.Set("RuntimeStats.enabled", false)
+	c.Set("statsSamplingRate", 0.5)
+
+	s := stats.NewStats(c, logger.NewFactory(c), metric.NewManager(), stats.WithoutBuildInfo())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// start stats
+	require.NoError(t, s.Start(ctx, stats.DefaultGoRoutineFactory))
+	defer s.Stop()
+
+	t.Run("enableStats", func(t *testing.T) {
+		counter := s.NewStat("test-reload-counter", stats.CountType)
+		counter.Count(1)
+		require.Eventually(t, func() bool {
+			return lastReceived.Load() == "test-reload-counter,instanceName=test:1|c"
+		}, 2*time.Second, time.Millisecond)
+
+		c.Set("enableStats", false)
+		counter.Count(2)
+		newCounter := s.NewStat("test-reload-new-counter", stats.CountType)
+		newCounter.Count(3)
+		require.Never(t, func() bool {
+			return lastReceived.Load() != "test-reload-counter,instanceName=test:1|c"
+		}, 100*time.Millisecond, time.Millisecond)
+
+		c.Set("enableStats", true)
+		counter.Count(4)
+		require.Eventually(t, func() bool {
+			return lastReceived.Load() == "test-reload-counter,instanceName=test:4|c"
+		}, 2*time.Second, time.Millisecond)
+		newCounter.Count(5)
+		require.Eventually(t, func() bool {
+			return lastReceived.Load() == "test-reload-new-counter,instanceName=test:5|c"
+		}, 2*time.Second, time.Millisecond)
+	})
+
+	t.Run("statsSamplingRate", func(t *testing.T) {
+		counterSampled := s.NewSampledTaggedStat("test-reload-sampled", stats.CountType, stats.Tags{"key": "value"})
+		require.Eventually(t, func() bool {
+			if lastReceived.Load() == "test-reload-sampled,instanceName=test,key=value:1|c|@0.5" {
+				return true
+			}
+			// playing with probabilities, we might or might not get the sample (0.5 -> 50% chance)
+			counterSampled.Increment()
+			return false
+		}, 2*time.Second, time.Millisecond)
+
+		c.Set("statsSamplingRate", 1.0)
+		counterSampled.Increment()
+		require.Eventually(t, func() bool {
+			return lastReceived.Load() == "test-reload-sampled,instanceName=test,key=value:1|c"
+		}, 2*time.Second, time.Millisecond)
+	})
+
+	t.Run("statsExcludedTags", func(t *testing.T) {
+		c.Set("statsExcludedTags", []string{"workspaceId"})
+		s.NewTaggedStat("test-reload-excluded", stats.CountType, stats.Tags{"workspaceId": "value", "a": "b"}).Increment()
+		require.Eventually(t, func() bool {
+			return lastReceived.Load() == "test-reload-excluded,instanceName=test,a=b:1|c"
+		}, 2*time.Second, time.Millisecond)
+	})
+}
+
 type statsdServer struct {
 	t      *testing.T
 	addr   string
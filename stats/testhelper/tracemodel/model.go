@@ -10,8 +10,8 @@ type Span struct {
 	StartTime              time.Time              `json:"StartTime"`
 	EndTime                time.Time              `json:"EndTime"`
 	Attributes             []Attributes           `json:"Attributes"`
-	Events                 any                    `json:"Events"`
-	Links                  any                    `json:"Links"`
+	Events                 []Event                `json:"Events"`
+	Links                  []Link                 `json:"Links"`
 	Status                 Status                 `json:"Status"`
 	DroppedAttributes      int                    `json:"DroppedAttributes"`
 	DroppedEvents          int                    `json:"DroppedEvents"`
@@ -35,13 +35,25 @@ type Parent struct {
 	Remote     bool   `json:"Remote"`
 }
 type Value struct {
-	Type  string `json:"Type"`
-	Value string `json:"Value"`
+	Type string `json:"Type"`
+	// Value is a string, a bool, a float64 (for both ints and floats) or a []any for slices
+	Value any `json:"Value"`
 }
 type Attributes struct {
 	Key   string `json:"Key"`
 	Value Value  `json:"Value"`
 }
+type Event struct {
+	Name                  string       `json:"Name"`
+	Attributes            []Attributes `json:"Attributes"`
+	DroppedAttributeCount int          `json:"DroppedAttributeCount"`
+	Time                  time.Time    `json:"Time"`
+}
+type Link struct {
+	SpanContext           SpanContext  `json:"SpanContext"`
+	Attributes            []Attributes `json:"Attributes"`
+	DroppedAttributeCount int          `json:"DroppedAttributeCount"`
+}
 type Status struct {
 	Code        string `json:"Code"`
 	Description string `json:"Description"`
@@ -2,8 +2,11 @@ package stats
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -84,10 +87,75 @@ type TraceSpan interface {
 	// the value contained in kv.
 	SetAttributes(tags Tags)
 
+	// SetTypedAttributes is like SetAttributes but allows values other than strings (e.g. int, bool, float, slices).
+	SetTypedAttributes(attrs ...Attribute)
+
+	// RecordError records err as an exception event for the Span. Use SpanWithStackTrace to also record the
+	// stack trace of the caller. The status of the Span is not changed, use SetStatus for that.
+	RecordError(err error, options ...SpanOption)
+
+	// AddLink adds a link to another Span that is causally related to this one (e.g. fan-in processing).
+	AddLink(link SpanLink)
+
 	// End terminates the span
 	End()
 }
 
+// Attribute is a typed key value pair that can be set on spans, events and links
+type Attribute = attribute.KeyValue
+
+// StringAttribute creates an Attribute with a string value
+func StringAttribute(k, v string) Attribute { return attribute.String(k, v) }
+
+// StringSliceAttribute creates an Attribute with a []string value
+func StringSliceAttribute(k string, v []string) Attribute { return attribute.StringSlice(k, v) }
+
+// IntAttribute creates an Attribute with an int value
+func IntAttribute(k string, v int) Attribute { return attribute.Int(k, v) }
+
+// IntSliceAttribute creates an Attribute with an []int value
+func IntSliceAttribute(k string, v []int) Attribute { return attribute.IntSlice(k, v) }
+
+// Int64Attribute creates an Attribute with an int64 value
+func Int64Attribute(k string, v int64) Attribute { return attribute.Int64(k, v) }
+
+// Int64SliceAttribute creates an Attribute with an []int64 value
+func Int64SliceAttribute(k string, v []int64) Attribute { return attribute.Int64Slice(k, v) }
+
+// Float64Attribute creates an Attribute with a float64 value
+func Float64Attribute(k string, v float64) Attribute { return attribute.Float64(k, v) }
+
+// Float64SliceAttribute creates an Attribute with a []float64 value
+func Float64SliceAttribute(k string, v []float64) Attribute { return attribute.Float64Slice(k, v) }
+
+// BoolAttribute creates an Attribute with a bool value
+func BoolAttribute(k string, v bool) Attribute { return attribute.Bool(k, v) }
+
+// BoolSliceAttribute creates an Attribute with a []bool value
+func BoolSliceAttribute(k string, v []bool) Attribute { return attribute.BoolSlice(k, v) }
+
+// SpanLink links a Span to another one which is causally related but is not its parent.
+// e.g. a span processing a batch of jobs can be linked to the spans that produced each job
+type SpanLink struct {
+	SpanContext SpanContext
+	Attributes  []Attribute
+}
+
+// SpanLinkFromContext returns a SpanLink to the Span in ctx
+func SpanLinkFromContext(ctx context.Context, attrs ...Attribute) SpanLink {
+	return SpanLink{SpanContext: trace.SpanContextFromContext(ctx), Attributes: attrs}
+}
+
+// SpanLinkFromTraceParent returns a SpanLink to the Span identified by the traceparent header
+// (see GetTraceParentFromContext)
+func SpanLinkFromTraceParent(traceParent string, attrs ...Attribute) SpanLink {
+	return SpanLinkFromContext(InjectTraceParentIntoContext(context.Background(), traceParent), attrs...)
+}
+
+func (l SpanLink) otelLink() trace.Link {
+	return trace.Link{SpanContext: l.SpanContext, Attributes: l.Attributes}
+}
+
 // NewTracerFromOpenTelemetry creates a new go-kit Tracer from an OpenTelemetry Tracer.
 func NewTracerFromOpenTelemetry(t trace.Tracer) Tracer {
 	return &tracer{tracer: t}
@@ -110,8 +178,11 @@ func (t *tracer) Start(ctx context.Context, spanName string, spanKind SpanKind,
 	}
 
 	var startOpts []trace.SpanStartOption
-	if len(sc.tags) > 0 {
-		startOpts = append(startOpts, trace.WithAttributes(sc.tags.otelAttributes()...))
+	if attrs := sc.otelAttributes(); len(attrs) > 0 {
+		startOpts = append(startOpts, trace.WithAttributes(attrs...))
+	}
+	for _, link := range sc.links {
+		startOpts = append(startOpts, trace.WithLinks(link.otelLink()))
 	}
 	if !sc.timestamp.IsZero() {
 		startOpts = append(startOpts, trace.WithTimestamp(sc.timestamp))
@@ -125,11 +196,22 @@ func (t *tracer) Start(ctx context.Context, spanName string, spanKind SpanKind,
 }
 
 type spanConfig struct {
-	tags      Tags
-	timestamp time.Time
+	tags           Tags
+	attributes     []Attribute
+	timestamp      time.Time
+	links          []SpanLink
+	withStackTrace bool
 }
 
-// SpanOption can be used with span.Start() and span.AddEvent()
+// otelAttributes returns the tags and the typed attributes as a list of OpenTelemetry attributes
+func (c *spanConfig) otelAttributes() []attribute.KeyValue {
+	if len(c.attributes) == 0 {
+		return c.tags.otelAttributes()
+	}
+	return append(c.tags.otelAttributes(), c.attributes...)
+}
+
+// SpanOption can be used with span.Start(), span.AddEvent() and span.RecordError()
 type SpanOption func(*spanConfig)
 
 // SpanWithTags sets the tags for the span
@@ -139,6 +221,13 @@ func SpanWithTags(tags Tags) SpanOption {
 	}
 }
 
+// SpanWithAttributes sets typed attributes for the span, they can be used together with SpanWithTags
+func SpanWithAttributes(attrs ...Attribute) SpanOption {
+	return func(c *spanConfig) {
+		c.attributes = append(c.attributes, attrs...)
+	}
+}
+
 // SpanWithTimestamp sets the timestamp for the span
 func SpanWithTimestamp(timestamp time.Time) SpanOption {
 	return func(c *spanConfig) {
@@ -146,6 +235,20 @@ func SpanWithTimestamp(timestamp time.Time) SpanOption {
 	}
 }
 
+// SpanWithLinks links the span to other spans (only applies to span.Start())
+func SpanWithLinks(links ...SpanLink) SpanOption {
+	return func(c *spanConfig) {
+		c.links = append(c.links, links...)
+	}
+}
+
+// SpanWithStackTrace records the stack trace of the caller (only applies to span.RecordError())
+func SpanWithStackTrace() SpanOption {
+	return func(c *spanConfig) {
+		c.withStackTrace = true
+	}
+}
+
 type span struct {
 	span trace.Span
 }
@@ -156,24 +259,43 @@ func (s *span) AddEvent(name string, opts ...SpanOption) {
 		opt(&sc)
 	}
 
-	var eventOpts []trace.EventOption
-	if len(sc.tags) > 0 {
-		eventOpts = append(eventOpts, trace.WithAttributes(sc.tags.otelAttributes()...))
+	s.span.AddEvent(name, sc.eventOptions()...)
+}
+
+func (s *span) RecordError(err error, opts ...SpanOption) {
+	var sc spanConfig
+	for _, opt := range opts {
+		opt(&sc)
 	}
-	if !sc.timestamp.IsZero() {
-		eventOpts = append(eventOpts, trace.WithTimestamp(sc.timestamp))
+
+	eventOpts := sc.eventOptions()
+	if sc.withStackTrace {
+		eventOpts = append(eventOpts, trace.WithStackTrace(true))
 	}
 
-	s.span.AddEvent(name, eventOpts...)
+	s.span.RecordError(err, eventOpts...)
+}
+
+func (c *spanConfig) eventOptions() []trace.EventOption {
+	var eventOpts []trace.EventOption
+	if attrs := c.otelAttributes(); len(attrs) > 0 {
+		eventOpts = append(eventOpts, trace.WithAttributes(attrs...))
+	}
+	if !c.timestamp.IsZero() {
+		eventOpts = append(eventOpts, trace.WithTimestamp(c.timestamp))
+	}
+	return eventOpts
 }
 
 func (s *span) SetStatus(code SpanStatus, description string) {
 	s.span.SetStatus(codes.Code(code), description)
 }
 
-func (s *span) SpanContext() SpanContext { return s.span.SpanContext() }
-func (s *span) SetAttributes(t Tags)     { s.span.SetAttributes(t.otelAttributes()...) }
-func (s *span) End()                     { s.span.End() }
+func (s *span) SpanContext() SpanContext              { return s.span.SpanContext() }
+func (s *span) SetAttributes(t Tags)                  { s.span.SetAttributes(t.otelAttributes()...) }
+func (s *span) SetTypedAttributes(attrs ...Attribute) { s.span.SetAttributes(attrs...) }
+func (s *span) AddLink(link SpanLink)                 { s.span.AddLink(link.otelLink()) }
+func (s *span) End()                                  { s.span.End() }
 
 const (
 	traceParentHeader = "traceparent"
@@ -192,3 +314,37 @@ func InjectTraceParentIntoContext(ctx context.Context, traceParent string) conte
 		traceParentHeader: traceParent,
 	})
 }
+
+// GetBaggageFromContext returns the baggage header from the context
+func GetBaggageFromContext(ctx context.Context) string {
+	return baggage.FromContext(ctx).String()
+}
+
+// InjectBaggageIntoContext injects the baggage header into the context.
+// If the header is invalid, the context is returned as is.
+func InjectBaggageIntoContext(ctx context.Context, header string) context.Context {
+	b, err := baggage.Parse(header)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, b)
+}
+
+// GetBaggageValue returns the value of the baggage member with the given key, or an empty string if it is missing
+func GetBaggageValue(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// SetBaggageValue returns a copy of the context whose baggage contains the given key and value.
+// Baggage is propagated along with the traceparent (e.g. via HTTP headers) so it can be read by downstream services.
+func SetBaggageValue(ctx context.Context, key, value string) (context.Context, error) {
+	m, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid baggage member %q: %w", key, err)
+	}
+	b, err := baggage.FromContext(ctx).SetMember(m)
+	if err != nil {
+		return ctx, fmt.Errorf("setting baggage member %q: %w", key, err)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}